@@ -0,0 +1,132 @@
+package supauth
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// banNone lifts a user's ban when sent as the ban duration.
+const banNone = "none"
+
+var ErrInvalidBanDuration = errors.New("ban duration must be positive")
+
+type AdminUserAttributes struct {
+	ID           string         `json:"id,omitempty"`
+	Email        string         `json:"email,omitempty"`
+	Phone        string         `json:"phone,omitempty"`
+	Password     string         `json:"password,omitempty"`
+	PasswordHash string         `json:"password_hash,omitempty"`
+	EmailConfirm bool           `json:"email_confirm,omitempty"`
+	PhoneConfirm bool           `json:"phone_confirm,omitempty"`
+	UserMetadata map[string]any `json:"user_metadata,omitempty"`
+	AppMetadata  map[string]any `json:"app_metadata,omitempty"`
+	// BanDuration is a Go duration string such as "24h", or "none" to lift a
+	// ban. BanUser and UnbanUser set it for you.
+	BanDuration string `json:"ban_duration,omitempty"`
+}
+
+// UserList is one page of users from ListUsers.
+type UserList struct {
+	Users []User `json:"users"`
+	Aud   string `json:"aud"`
+}
+
+type deleteUserRequest struct {
+	ShouldSoftDelete bool `json:"should_soft_delete"`
+}
+
+type AdminInterface interface {
+	CreateUser(attributes AdminUserAttributes) (*AuthResponse, error)
+	GetUserByID(id string) (*AuthResponse, error)
+	ListUsers(page, perPage int) (*AuthResponse, error)
+	UpdateUserByID(id string, attributes AdminUserAttributes) (*AuthResponse, error)
+	ModifyAppMetadata(userID string, modify func(appMetadata map[string]any)) (*AuthResponse, error)
+	GrantRoles(userID string, roles ...string) (*AuthResponse, error)
+	RevokeRoles(userID string, roles ...string) (*AuthResponse, error)
+	GrantPermissions(userID string, permissions ...string) (*AuthResponse, error)
+	RevokePermissions(userID string, permissions ...string) (*AuthResponse, error)
+	BanUser(id string, duration time.Duration) (*AuthResponse, error)
+	UnbanUser(id string) (*AuthResponse, error)
+	DeleteUser(id string, soft bool) (*AuthResponse, error)
+	InviteUserByEmail(email string, options AuthOptions) (*AuthResponse, error)
+	GenerateLink(params GenerateLinkParams) (*AuthResponse, error)
+	ListUserFactors(userID string) (*AuthResponse, error)
+	DeleteUserFactor(userID, factorID string) (*AuthResponse, error)
+	ListSSOProviders() (*AuthResponse, error)
+	CreateSSOProvider(attributes SSOProviderAttributes) (*AuthResponse, error)
+	GetSSOProvider(id string) (*AuthResponse, error)
+	UpdateSSOProvider(id string, attributes SSOProviderAttributes) (*AuthResponse, error)
+	DeleteSSOProvider(id string) (*AuthResponse, error)
+}
+
+// Admin calls the GoTrue admin API, which must be authorised with the
+// project's service role key. Never use it from client-side code.
+type Admin struct {
+	client         clientInterface
+	serviceRoleKey string
+}
+
+func NewAdmin(projectId string, serviceRoleKey string) *Admin {
+	client := newClient(projectId, serviceRoleKey)
+
+	return &Admin{
+		client:         client,
+		serviceRoleKey: serviceRoleKey,
+	}
+}
+
+func (a *Admin) CreateUser(attributes AdminUserAttributes) (*AuthResponse, error) {
+	successResponse := &User{}
+
+	return a.client.createAndSendRequestWithToken(http.MethodPost, "admin/users", a.serviceRoleKey, attributes, successResponse)
+}
+
+func (a *Admin) GetUserByID(id string) (*AuthResponse, error) {
+	successResponse := &User{}
+
+	return a.client.createAndSendRequestWithToken(http.MethodGet, adminUserEndpoint(id), a.serviceRoleKey, nil, successResponse)
+}
+
+// ListUsers returns one page of the project's users as *UserList. Pages
+// start at 1; a page shorter than perPage is the last one.
+func (a *Admin) ListUsers(page, perPage int) (*AuthResponse, error) {
+	successResponse := &UserList{}
+	endpoint := fmt.Sprintf("admin/users?page=%d&per_page=%d", page, perPage)
+
+	return a.client.createAndSendRequestWithToken(http.MethodGet, endpoint, a.serviceRoleKey, nil, successResponse)
+}
+
+func (a *Admin) UpdateUserByID(id string, attributes AdminUserAttributes) (*AuthResponse, error) {
+	successResponse := &User{}
+
+	return a.client.createAndSendRequestWithToken(http.MethodPut, adminUserEndpoint(id), a.serviceRoleKey, attributes, successResponse)
+}
+
+// BanUser stops a user signing in or refreshing their session until duration
+// has passed. The ban end is returned as User.BannedUntil.
+func (a *Admin) BanUser(id string, duration time.Duration) (*AuthResponse, error) {
+	if duration <= 0 {
+		return nil, ErrInvalidBanDuration
+	}
+
+	return a.UpdateUserByID(id, AdminUserAttributes{BanDuration: duration.String()})
+}
+
+func (a *Admin) UnbanUser(id string) (*AuthResponse, error) {
+	return a.UpdateUserByID(id, AdminUserAttributes{BanDuration: banNone})
+}
+
+// DeleteUser removes a user. A soft delete keeps the row, sets DeletedAt and
+// obfuscates the user's personal data instead, so they can no longer sign in.
+func (a *Admin) DeleteUser(id string, soft bool) (*AuthResponse, error) {
+	reqBody := deleteUserRequest{ShouldSoftDelete: soft}
+
+	return a.client.createAndSendRequestWithToken(http.MethodDelete, adminUserEndpoint(id), a.serviceRoleKey, reqBody, nil)
+}
+
+func adminUserEndpoint(id string) string {
+	return fmt.Sprintf("admin/users/%s", url.PathEscape(id))
+}
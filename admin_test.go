@@ -0,0 +1,282 @@
+package supauth
+
+import (
+	"errors"
+	"github.com/go-playground/assert/v2"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"testing"
+	"time"
+)
+
+type adminMock struct {
+	mock.Mock
+}
+
+func (a *adminMock) CreateUser(attributes AdminUserAttributes) (*AuthResponse, error) {
+	args := a.Called(attributes)
+	return args.Get(0).(*AuthResponse), args.Error(1)
+}
+
+func (a *adminMock) GetUserByID(id string) (*AuthResponse, error) {
+	args := a.Called(id)
+	return args.Get(0).(*AuthResponse), args.Error(1)
+}
+
+func (a *adminMock) ListUsers(page, perPage int) (*AuthResponse, error) {
+	args := a.Called(page, perPage)
+	return args.Get(0).(*AuthResponse), args.Error(1)
+}
+
+func (a *adminMock) ModifyAppMetadata(userID string, modify func(appMetadata map[string]any)) (*AuthResponse, error) {
+	args := a.Called(userID, modify)
+	return args.Get(0).(*AuthResponse), args.Error(1)
+}
+
+func (a *adminMock) GrantRoles(userID string, roles ...string) (*AuthResponse, error) {
+	args := a.Called(userID, roles)
+	return args.Get(0).(*AuthResponse), args.Error(1)
+}
+
+func (a *adminMock) RevokeRoles(userID string, roles ...string) (*AuthResponse, error) {
+	args := a.Called(userID, roles)
+	return args.Get(0).(*AuthResponse), args.Error(1)
+}
+
+func (a *adminMock) GrantPermissions(userID string, permissions ...string) (*AuthResponse, error) {
+	args := a.Called(userID, permissions)
+	return args.Get(0).(*AuthResponse), args.Error(1)
+}
+
+func (a *adminMock) RevokePermissions(userID string, permissions ...string) (*AuthResponse, error) {
+	args := a.Called(userID, permissions)
+	return args.Get(0).(*AuthResponse), args.Error(1)
+}
+
+func (a *adminMock) UpdateUserByID(id string, attributes AdminUserAttributes) (*AuthResponse, error) {
+	args := a.Called(id, attributes)
+	return args.Get(0).(*AuthResponse), args.Error(1)
+}
+
+func (a *adminMock) BanUser(id string, duration time.Duration) (*AuthResponse, error) {
+	args := a.Called(id, duration)
+	return args.Get(0).(*AuthResponse), args.Error(1)
+}
+
+func (a *adminMock) UnbanUser(id string) (*AuthResponse, error) {
+	args := a.Called(id)
+	return args.Get(0).(*AuthResponse), args.Error(1)
+}
+
+func (a *adminMock) DeleteUser(id string, soft bool) (*AuthResponse, error) {
+	args := a.Called(id, soft)
+	return args.Get(0).(*AuthResponse), args.Error(1)
+}
+
+func (a *adminMock) InviteUserByEmail(email string, options AuthOptions) (*AuthResponse, error) {
+	args := a.Called(email, options)
+	return args.Get(0).(*AuthResponse), args.Error(1)
+}
+
+func (a *adminMock) GenerateLink(params GenerateLinkParams) (*AuthResponse, error) {
+	args := a.Called(params)
+	return args.Get(0).(*AuthResponse), args.Error(1)
+}
+
+func (a *adminMock) ListUserFactors(userID string) (*AuthResponse, error) {
+	args := a.Called(userID)
+	return args.Get(0).(*AuthResponse), args.Error(1)
+}
+
+func (a *adminMock) DeleteUserFactor(userID, factorID string) (*AuthResponse, error) {
+	args := a.Called(userID, factorID)
+	return args.Get(0).(*AuthResponse), args.Error(1)
+}
+
+func (a *adminMock) ListSSOProviders() (*AuthResponse, error) {
+	args := a.Called()
+	return args.Get(0).(*AuthResponse), args.Error(1)
+}
+
+func (a *adminMock) CreateSSOProvider(attributes SSOProviderAttributes) (*AuthResponse, error) {
+	args := a.Called(attributes)
+	return args.Get(0).(*AuthResponse), args.Error(1)
+}
+
+func (a *adminMock) GetSSOProvider(id string) (*AuthResponse, error) {
+	args := a.Called(id)
+	return args.Get(0).(*AuthResponse), args.Error(1)
+}
+
+func (a *adminMock) UpdateSSOProvider(id string, attributes SSOProviderAttributes) (*AuthResponse, error) {
+	args := a.Called(id, attributes)
+	return args.Get(0).(*AuthResponse), args.Error(1)
+}
+
+func (a *adminMock) DeleteSSOProvider(id string) (*AuthResponse, error) {
+	args := a.Called(id)
+	return args.Get(0).(*AuthResponse), args.Error(1)
+}
+
+func TestNewAdmin(t *testing.T) {
+	project := "test"
+	serviceRoleKey := "service123"
+
+	admin := NewAdmin(project, serviceRoleKey)
+
+	assert.NotEqual(t, nil, admin.client)
+	assert.Equal(t, admin.serviceRoleKey, serviceRoleKey)
+}
+
+var createUserTests = []struct {
+	name           string
+	authResponse   *AuthResponse
+	sendRequestErr error
+	resultErr      error
+}{
+	{
+		name: "successful create user",
+		authResponse: &AuthResponse{
+			Status: http.StatusOK,
+			Data: &User{
+				ID:    "abc123",
+				Email: "test@example.com",
+			},
+		},
+		sendRequestErr: nil,
+		resultErr:      nil,
+	},
+	{
+		name:           "failed create user with send request error",
+		authResponse:   nil,
+		sendRequestErr: errors.New("send request error"),
+		resultErr:      errors.New("send request error"),
+	},
+}
+
+func TestAdmin_CreateUser(t *testing.T) {
+	for _, tt := range createUserTests {
+		client := new(clientMock)
+		sut := &Admin{
+			client:         client,
+			serviceRoleKey: "service123",
+		}
+		attributes := AdminUserAttributes{
+			Email:        "test@example.com",
+			PasswordHash: "$2a$10$abc",
+			EmailConfirm: true,
+		}
+
+		client.On("createAndSendRequestWithToken", http.MethodPost, "admin/users", "service123", attributes, &User{}).
+			Return(tt.authResponse, tt.sendRequestErr)
+
+		result, err := sut.CreateUser(attributes)
+
+		if err != nil {
+			assert.Equal(t, err.Error(), tt.resultErr.Error())
+			assert.Equal(t, result, tt.authResponse)
+		} else {
+			assert.Equal(t, err, nil)
+			assert.Equal(t, result, tt.authResponse)
+		}
+	}
+}
+
+var adminUserTests = []struct {
+	name                 string
+	call                 func(sut *Admin) (*AuthResponse, error)
+	expectedMethod       string
+	expectedEndpoint     string
+	expectedData         any
+	expectedSuccessValue any
+}{
+	{
+		name:                 "get user",
+		call:                 func(sut *Admin) (*AuthResponse, error) { return sut.GetUserByID("abc/123") },
+		expectedMethod:       http.MethodGet,
+		expectedEndpoint:     "admin/users/abc%2F123",
+		expectedData:         nil,
+		expectedSuccessValue: &User{},
+	},
+	{
+		name:                 "list users",
+		call:                 func(sut *Admin) (*AuthResponse, error) { return sut.ListUsers(2, 50) },
+		expectedMethod:       http.MethodGet,
+		expectedEndpoint:     "admin/users?page=2&per_page=50",
+		expectedData:         nil,
+		expectedSuccessValue: &UserList{},
+	},
+	{
+		name: "update user",
+		call: func(sut *Admin) (*AuthResponse, error) {
+			return sut.UpdateUserByID("abc/123", AdminUserAttributes{Email: "new@example.com", EmailConfirm: true})
+		},
+		expectedMethod:       http.MethodPut,
+		expectedEndpoint:     "admin/users/abc%2F123",
+		expectedData:         AdminUserAttributes{Email: "new@example.com", EmailConfirm: true},
+		expectedSuccessValue: &User{},
+	},
+	{
+		name:                 "ban user",
+		call:                 func(sut *Admin) (*AuthResponse, error) { return sut.BanUser("abc123", 36*time.Hour) },
+		expectedMethod:       http.MethodPut,
+		expectedEndpoint:     "admin/users/abc123",
+		expectedData:         AdminUserAttributes{BanDuration: "36h0m0s"},
+		expectedSuccessValue: &User{},
+	},
+	{
+		name:                 "unban user",
+		call:                 func(sut *Admin) (*AuthResponse, error) { return sut.UnbanUser("abc123") },
+		expectedMethod:       http.MethodPut,
+		expectedEndpoint:     "admin/users/abc123",
+		expectedData:         AdminUserAttributes{BanDuration: "none"},
+		expectedSuccessValue: &User{},
+	},
+	{
+		name:                 "hard delete user",
+		call:                 func(sut *Admin) (*AuthResponse, error) { return sut.DeleteUser("abc123", false) },
+		expectedMethod:       http.MethodDelete,
+		expectedEndpoint:     "admin/users/abc123",
+		expectedData:         deleteUserRequest{ShouldSoftDelete: false},
+		expectedSuccessValue: nil,
+	},
+	{
+		name:                 "soft delete user",
+		call:                 func(sut *Admin) (*AuthResponse, error) { return sut.DeleteUser("abc123", true) },
+		expectedMethod:       http.MethodDelete,
+		expectedEndpoint:     "admin/users/abc123",
+		expectedData:         deleteUserRequest{ShouldSoftDelete: true},
+		expectedSuccessValue: nil,
+	},
+}
+
+func TestAdmin_Users(t *testing.T) {
+	for _, tt := range adminUserTests {
+		client := new(clientMock)
+		sut := &Admin{
+			client:         client,
+			serviceRoleKey: "service123",
+		}
+		authResponse := &AuthResponse{Status: http.StatusOK, Data: tt.expectedSuccessValue}
+
+		client.On("createAndSendRequestWithToken", tt.expectedMethod, tt.expectedEndpoint, "service123", tt.expectedData, tt.expectedSuccessValue).
+			Return(authResponse, nil)
+
+		result, err := tt.call(sut)
+
+		assert.Equal(t, err, nil)
+		assert.Equal(t, result, authResponse)
+	}
+}
+
+func TestAdmin_BanUserInvalidDuration(t *testing.T) {
+	sut := &Admin{
+		client:         new(clientMock),
+		serviceRoleKey: "service123",
+	}
+
+	result, err := sut.BanUser("abc123", 0)
+
+	assert.Equal(t, result, nil)
+	assert.Equal(t, err, ErrInvalidBanDuration)
+}
@@ -3,54 +3,193 @@ package supauth
 import (
 	"fmt"
 	"net/http"
-	"time"
+	"net/url"
+	"strings"
+)
+
+type OtpType string
+
+const (
+	OtpTypeSignup      OtpType = "signup"
+	OtpTypeInvite      OtpType = "invite"
+	OtpTypeMagicLink   OtpType = "magiclink"
+	OtpTypeRecovery    OtpType = "recovery"
+	OtpTypeEmail       OtpType = "email"
+	OtpTypeEmailChange OtpType = "email_change"
+	OtpTypeSMS         OtpType = "sms"
+	OtpTypePhoneChange OtpType = "phone_change"
+)
+
+type SignOutScope string
+
+const (
+	// SignOutScopeGlobal ends every session the user has. GoTrue uses it when
+	// no scope is given.
+	SignOutScopeGlobal SignOutScope = "global"
+	// SignOutScopeLocal ends only the session the access token belongs to.
+	SignOutScopeLocal SignOutScope = "local"
+	// SignOutScopeOthers ends every session except the current one.
+	SignOutScopeOthers SignOutScope = "others"
 )
 
 type UserCredentials struct {
-	Email    string
-	Password string
+	Email    string      `json:"email"`
+	Password string      `json:"password"`
+	Options  AuthOptions `json:"-"`
+}
+
+type credentialsRequest struct {
+	UserCredentials
+	Data     map[string]any `json:"data,omitempty"`
+	Security *metaSecurity  `json:"gotrue_meta_security,omitempty"`
+}
+
+type IdTokenCredentials struct {
+	// Provider is the OIDC provider that issued the token, such as "apple",
+	// "google", "azure", "facebook" or "keycloak".
+	Provider string `json:"provider"`
+	IdToken  string `json:"id_token"`
+	// AccessToken is needed when the ID token has an at_hash claim.
+	AccessToken string `json:"access_token,omitempty"`
+	// Nonce is the raw nonce whose hash was passed to the provider's SDK.
+	Nonce   string      `json:"nonce,omitempty"`
+	Options AuthOptions `json:"-"`
+}
+
+type idTokenRequest struct {
+	IdTokenCredentials
+	Security *metaSecurity `json:"gotrue_meta_security,omitempty"`
+}
+
+type anonymousRequest struct {
+	Data     map[string]any `json:"data,omitempty"`
+	Security *metaSecurity  `json:"gotrue_meta_security,omitempty"`
+}
+
+type OtpParams struct {
+	Email string `json:"email,omitempty"`
+	Phone string `json:"phone,omitempty"`
+	// Channel sends a phone OTP over "sms" (the default) or "whatsapp".
+	Channel string `json:"channel,omitempty"`
+	// DisableSignUp stops GoTrue creating a user when none exists yet.
+	DisableSignUp bool        `json:"-"`
+	Options       AuthOptions `json:"-"`
+}
+
+type otpRequest struct {
+	OtpParams
+	CreateUser bool           `json:"create_user"`
+	Data       map[string]any `json:"data,omitempty"`
+	Security   *metaSecurity  `json:"gotrue_meta_security,omitempty"`
+}
+
+type recoverRequest struct {
+	Email    string        `json:"email"`
+	Security *metaSecurity `json:"gotrue_meta_security,omitempty"`
 }
 
 type SignUp struct {
-	ID                 string    `json:"id"`
-	Email              string    `json:"email"`
-	ConfirmedAt        time.Time `json:"confirmed_at"`
-	ConfirmationSentAt time.Time `json:"confirmation_sent_at"`
-	CreatedAt          time.Time `json:"created_at"`
-	UpdatedAt          time.Time `json:"updated_at"`
-}
-
-type User struct {
-	ID                 string                    `json:"id"`
-	Aud                string                    `json:"aud"`
-	Role               string                    `json:"role"`
-	Email              string                    `json:"email"`
-	InvitedAt          time.Time                 `json:"invited_at"`
-	ConfirmedAt        time.Time                 `json:"confirmed_at"`
-	ConfirmationSentAt time.Time                 `json:"confirmation_sent_at"`
-	AppMetadata        struct{ provider string } `json:"app_metadata"`
-	UserMetadata       map[string]interface{}    `json:"user_metadata"`
-	CreatedAt          time.Time                 `json:"created_at"`
-	UpdatedAt          time.Time                 `json:"updated_at"`
+	ID                 string   `json:"id"`
+	Email              string   `json:"email"`
+	ConfirmedAt        NullTime `json:"confirmed_at"`
+	ConfirmationSentAt NullTime `json:"confirmation_sent_at"`
+	CreatedAt          NullTime `json:"created_at"`
+	UpdatedAt          NullTime `json:"updated_at"`
+}
+
+type UserAttributes struct {
+	Email    string         `json:"email,omitempty"`
+	Phone    string         `json:"phone,omitempty"`
+	Password string         `json:"password,omitempty"`
+	Nonce    string         `json:"nonce,omitempty"`
+	Data     map[string]any `json:"data,omitempty"`
 }
 
 type Authenticated struct {
 	AccessToken          string `json:"access_token"`
 	TokenType            string `json:"token_type"`
 	ExpiresIn            int    `json:"expires_in"`
+	ExpiresAt            int64  `json:"expires_at"`
 	RefreshToken         string `json:"refresh_token"`
 	User                 User   `json:"user"`
 	ProviderToken        string `json:"provider_token"`
 	ProviderRefreshToken string `json:"provider_refresh_token"`
 }
 
+type VerifyOtpParams struct {
+	Type      OtpType `json:"type"`
+	Email     string  `json:"email,omitempty"`
+	Phone     string  `json:"phone,omitempty"`
+	Token     string  `json:"token,omitempty"`
+	TokenHash string  `json:"token_hash,omitempty"`
+}
+
+type AuthOptions struct {
+	// RedirectTo is where links in the email send the user. It must be in the
+	// project's redirect allow list, otherwise the site URL is used.
+	RedirectTo string
+	// CaptchaToken is required when the project has captcha protection on.
+	CaptchaToken string
+	// Data is the initial user metadata for a user being signed up.
+	Data map[string]any
+}
+
+type metaSecurity struct {
+	CaptchaToken string `json:"captcha_token"`
+}
+
+type ResendParams struct {
+	Type    OtpType     `json:"type"`
+	Email   string      `json:"email,omitempty"`
+	Phone   string      `json:"phone,omitempty"`
+	Options AuthOptions `json:"-"`
+}
+
+type resendRequest struct {
+	ResendParams
+	Security *metaSecurity `json:"gotrue_meta_security,omitempty"`
+}
+
+// Verified is returned by VerifyOtp. When secure email change is enabled the
+// first of the two email_change confirmations only returns a Message, and no
+// session is issued until the other address is confirmed too.
+type Verified struct {
+	Authenticated
+	Message string `json:"msg"`
+}
+
+func (v *Verified) AwaitingOtherConfirmation() bool {
+	return v.AccessToken == "" && v.Message != ""
+}
+
 type AuthInterface interface {
 	SignUp(credentials UserCredentials) (*AuthResponse, error)
 	SignIn(credentials UserCredentials) (*AuthResponse, error)
-	SignOut(token string) (*AuthResponse, error)
+	SignInWithOtp(params OtpParams) (*AuthResponse, error)
+	SignInAnonymously(options AuthOptions) (*AuthResponse, error)
+	SignInWithIdToken(credentials IdTokenCredentials) (*AuthResponse, error)
+	SignInWithSSO(params SSOParams) (*AuthResponse, error)
+	SignInWithWeb3(credentials Web3Credentials) (*AuthResponse, error)
+	ExchangeCodeForSession(authCode, codeVerifier string) (*AuthResponse, error)
+	LinkIdentity(accessToken string, params LinkIdentityParams) (*AuthResponse, error)
+	UnlinkIdentity(accessToken, identityID string) (*AuthResponse, error)
+	GetUserIdentities(accessToken string) (*AuthResponse, error)
+	EnrollFactor(accessToken string, params EnrollFactorParams) (*AuthResponse, error)
+	ChallengeFactor(accessToken, factorID string, params ChallengeFactorParams) (*AuthResponse, error)
+	VerifyFactor(accessToken, factorID string, params VerifyFactorParams) (*AuthResponse, error)
+	UnenrollFactor(accessToken, factorID string) (*AuthResponse, error)
+	SignOut(token string, scope ...SignOutScope) (*AuthResponse, error)
 	RefreshToken(refreshToken string) (*AuthResponse, error)
-	ForgottenPassword(email string) (*AuthResponse, error)
+	ForgottenPassword(email string, options ...AuthOptions) (*AuthResponse, error)
 	ResetPassword(token, password string) (*AuthResponse, error)
+	GetUser(accessToken string) (*AuthResponse, error)
+	UpdateUser(accessToken string, attributes UserAttributes) (*AuthResponse, error)
+	ChangeEmail(accessToken, email string) (*AuthResponse, error)
+	ChangePhone(accessToken, phone string) (*AuthResponse, error)
+	VerifyOtp(params VerifyOtpParams) (*AuthResponse, error)
+	Resend(params ResendParams) (*AuthResponse, error)
+	Reauthenticate(accessToken string) (*AuthResponse, error)
+	UpdatePassword(accessToken, password, nonce string) (*AuthResponse, error)
 }
 
 type Auth struct {
@@ -66,19 +205,85 @@ func NewAuth(projectId string, apiKey string) *Auth {
 }
 
 func (a *Auth) SignUp(credentials UserCredentials) (*AuthResponse, error) {
+	reqBody := credentialsRequest{
+		UserCredentials: credentials,
+		Data:            credentials.Options.Data,
+		Security:        credentials.Options.security(),
+	}
+
 	successResponse := &SignUp{}
 
-	return a.client.createAndSendRequest(http.MethodPost, "signup", credentials, successResponse)
+	return a.client.createAndSendRequest(http.MethodPost, credentials.Options.endpoint("signup"), reqBody, successResponse)
 }
 
+// SignIn only uses the captcha token from the credentials' options, as a
+// password sign in sends no email and creates no user.
 func (a *Auth) SignIn(credentials UserCredentials) (*AuthResponse, error) {
+	reqBody := credentialsRequest{
+		UserCredentials: credentials,
+		Security:        credentials.Options.security(),
+	}
+
+	successResponse := &Authenticated{}
+
+	return a.client.createAndSendRequest(http.MethodPost, "token?grant_type=password", reqBody, successResponse)
+}
+
+// SignInWithIdToken exchanges an ID token from a native sign in SDK, such as
+// Sign in with Apple or Google Sign-In, for a session.
+func (a *Auth) SignInWithIdToken(credentials IdTokenCredentials) (*AuthResponse, error) {
+	reqBody := idTokenRequest{
+		IdTokenCredentials: credentials,
+		Security:           credentials.Options.security(),
+	}
+
+	successResponse := &Authenticated{}
+
+	return a.client.createAndSendRequest(http.MethodPost, "token?grant_type=id_token", reqBody, successResponse)
+}
+
+// SignInAnonymously creates a user with no email, phone or password and signs
+// them in. The user has IsAnonymous set until they are made permanent by
+// adding an email or phone with UpdateUser and verifying it.
+func (a *Auth) SignInAnonymously(options AuthOptions) (*AuthResponse, error) {
+	reqBody := anonymousRequest{
+		Data:     options.Data,
+		Security: options.security(),
+	}
+
 	successResponse := &Authenticated{}
 
-	return a.client.createAndSendRequest(http.MethodPost, "token?grant_type=password", credentials, successResponse)
+	return a.client.createAndSendRequest(http.MethodPost, "signup", reqBody, successResponse)
 }
 
-func (a *Auth) SignOut(token string) (*AuthResponse, error) {
-	req, err := a.client.createRequest(http.MethodPost, "logout", nil)
+// SignInWithOtp sends a magic link or OTP to an email, or an OTP to a phone.
+// The code is then exchanged for a session with VerifyOtp.
+func (a *Auth) SignInWithOtp(params OtpParams) (*AuthResponse, error) {
+	reqBody := otpRequest{
+		OtpParams:  params,
+		CreateUser: !params.DisableSignUp,
+		Data:       params.Options.Data,
+		Security:   params.Options.security(),
+	}
+
+	authResponse, err := a.client.createAndSendRequest(http.MethodPost, params.Options.endpoint("otp"), reqBody, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return authResponse, cooldownError(authResponse)
+}
+
+// SignOut revokes the user's refresh tokens for the given scope, which
+// defaults to SignOutScopeGlobal. Only the first scope, if any, is used.
+// Access tokens stay valid until they expire.
+func (a *Auth) SignOut(token string, scope ...SignOutScope) (*AuthResponse, error) {
+	endpoint := "logout"
+	if len(scope) > 0 {
+		endpoint = fmt.Sprintf("logout?scope=%s", scope[0])
+	}
+
+	req, err := a.client.createRequest(http.MethodPost, endpoint, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -101,10 +306,20 @@ func (a *Auth) RefreshToken(refreshToken string) (*AuthResponse, error) {
 	return a.client.createAndSendRequest(http.MethodPost, "token?grant_type=refresh_token", reqBody, successResponse)
 }
 
-func (a *Auth) ForgottenPassword(email string) (*AuthResponse, error) {
-	reqBody := map[string]string{"email": email}
+// ForgottenPassword sends a password recovery email. Only the first options
+// value, if any, is used.
+func (a *Auth) ForgottenPassword(email string, options ...AuthOptions) (*AuthResponse, error) {
+	opts := AuthOptions{}
+	if len(options) > 0 {
+		opts = options[0]
+	}
 
-	return a.client.createAndSendRequest(http.MethodPost, "recover", reqBody, nil)
+	reqBody := recoverRequest{
+		Email:    email,
+		Security: opts.security(),
+	}
+
+	return a.client.createAndSendRequest(http.MethodPost, opts.endpoint("recover"), reqBody, nil)
 }
 
 func (a *Auth) ResetPassword(token, password string) (*AuthResponse, error) {
@@ -123,3 +338,85 @@ func (a *Auth) ResetPassword(token, password string) (*AuthResponse, error) {
 
 	return authResponse, nil
 }
+
+func (a *Auth) GetUser(accessToken string) (*AuthResponse, error) {
+	successResponse := &User{}
+
+	return a.client.createAndSendRequestWithToken(http.MethodGet, "user", accessToken, nil, successResponse)
+}
+
+func (a *Auth) UpdateUser(accessToken string, attributes UserAttributes) (*AuthResponse, error) {
+	successResponse := &User{}
+
+	return a.client.createAndSendRequestWithToken(http.MethodPut, "user", accessToken, attributes, successResponse)
+}
+
+// ChangeEmail starts an email change. GoTrue sends an email_change OTP to the
+// new address and, with secure email change enabled, to the current one as
+// well; both must be passed to VerifyOtp before Email is updated.
+func (a *Auth) ChangeEmail(accessToken, email string) (*AuthResponse, error) {
+	return a.UpdateUser(accessToken, UserAttributes{Email: email})
+}
+
+// ChangePhone starts a phone change. GoTrue sends a phone_change OTP to the new
+// number, which must be passed to VerifyOtp before Phone is updated.
+func (a *Auth) ChangePhone(accessToken, phone string) (*AuthResponse, error) {
+	return a.UpdateUser(accessToken, UserAttributes{Phone: phone})
+}
+
+func (a *Auth) VerifyOtp(params VerifyOtpParams) (*AuthResponse, error) {
+	successResponse := &Verified{}
+
+	return a.client.createAndSendRequest(http.MethodPost, "verify", params, successResponse)
+}
+
+// Resend sends the signup, email_change, sms or phone_change OTP again. When
+// GoTrue asks the user to wait, the response is returned with a
+// *CooldownError holding the time left.
+func (a *Auth) Resend(params ResendParams) (*AuthResponse, error) {
+	reqBody := resendRequest{
+		ResendParams: params,
+		Security:     params.Options.security(),
+	}
+
+	authResponse, err := a.client.createAndSendRequest(http.MethodPost, params.Options.endpoint("resend"), reqBody, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return authResponse, cooldownError(authResponse)
+}
+
+func (o AuthOptions) endpoint(endpoint string) string {
+	if o.RedirectTo == "" {
+		return endpoint
+	}
+
+	separator := "?"
+	if strings.Contains(endpoint, "?") {
+		separator = "&"
+	}
+
+	return fmt.Sprintf("%s%sredirect_to=%s", endpoint, separator, url.QueryEscape(o.RedirectTo))
+}
+
+func (o AuthOptions) security() *metaSecurity {
+	if o.CaptchaToken == "" {
+		return nil
+	}
+
+	return &metaSecurity{CaptchaToken: o.CaptchaToken}
+}
+
+// Reauthenticate sends the signed in user a one-time nonce by email or SMS.
+// Projects with secure password change enabled require it in UpdatePassword
+// when the user has not signed in recently.
+func (a *Auth) Reauthenticate(accessToken string) (*AuthResponse, error) {
+	return a.client.createAndSendRequestWithToken(http.MethodGet, "reauthenticate", accessToken, nil, nil)
+}
+
+// UpdatePassword changes the signed in user's password. Pass the nonce from
+// Reauthenticate, or an empty string if the project does not require one.
+func (a *Auth) UpdatePassword(accessToken, password, nonce string) (*AuthResponse, error) {
+	return a.UpdateUser(accessToken, UserAttributes{Password: password, Nonce: nonce})
+}
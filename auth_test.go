@@ -1,6 +1,7 @@
 package supauth
 
 import (
+	"encoding/json"
 	"errors"
 	"github.com/go-playground/assert/v2"
 	"github.com/stretchr/testify/mock"
@@ -21,6 +22,11 @@ func (c *clientMock) createAndSendRequest(method, endpoint string, data, success
 	return args.Get(0).(*AuthResponse), args.Error(1)
 }
 
+func (c *clientMock) createAndSendRequestWithToken(method, endpoint, token string, data, successValue any) (*AuthResponse, error) {
+	args := c.Called(method, endpoint, token, data, successValue)
+	return args.Get(0).(*AuthResponse), args.Error(1)
+}
+
 func (c *clientMock) createRequest(method, endpoint string, data any) (*http.Request, error) {
 	args := c.Called(method, endpoint, data)
 	return args.Get(0).(*http.Request), args.Error(1)
@@ -77,7 +83,7 @@ func TestAuth_SignUp(t *testing.T) {
 			Password: "password",
 		}
 
-		client.On("createAndSendRequest", http.MethodPost, "signup", creds, &SignUp{}).
+		client.On("createAndSendRequest", http.MethodPost, "signup", credentialsRequest{UserCredentials: creds}, &SignUp{}).
 			Return(tt.authResponse, tt.sendRequestErr)
 
 		result, err := sut.SignUp(creds)
@@ -92,6 +98,299 @@ func TestAuth_SignUp(t *testing.T) {
 	}
 }
 
+func TestAuth_SignUpWithOptions(t *testing.T) {
+	client := new(clientMock)
+	sut := &Auth{
+		client: client,
+	}
+	creds := UserCredentials{
+		Email:    "test@example.com",
+		Password: "password",
+		Options: AuthOptions{
+			RedirectTo:   "https://example.com/welcome",
+			CaptchaToken: "captcha123",
+			Data:         map[string]any{"name": "Test"},
+		},
+	}
+	reqBody := credentialsRequest{
+		UserCredentials: creds,
+		Data:            map[string]any{"name": "Test"},
+		Security:        &metaSecurity{CaptchaToken: "captcha123"},
+	}
+	authResponse := &AuthResponse{Status: http.StatusOK, Data: &SignUp{ID: "abc123"}}
+
+	client.On("createAndSendRequest", http.MethodPost, "signup?redirect_to=https%3A%2F%2Fexample.com%2Fwelcome", reqBody, &SignUp{}).
+		Return(authResponse, nil)
+
+	result, err := sut.SignUp(creds)
+
+	assert.Equal(t, err, nil)
+	assert.Equal(t, result, authResponse)
+}
+
+func TestCredentialsRequest_MarshalJSON(t *testing.T) {
+	reqBody := credentialsRequest{
+		UserCredentials: UserCredentials{
+			Email:    "test@example.com",
+			Password: "password",
+			Options:  AuthOptions{RedirectTo: "https://example.com", CaptchaToken: "captcha123"},
+		},
+		Data:     map[string]any{"name": "Test"},
+		Security: &metaSecurity{CaptchaToken: "captcha123"},
+	}
+
+	data, err := json.Marshal(reqBody)
+
+	assert.Equal(t, err, nil)
+	assert.Equal(t, string(data), `{"email":"test@example.com","password":"password","data":{"name":"Test"},"gotrue_meta_security":{"captcha_token":"captcha123"}}`)
+}
+
+var signInWithOtpTests = []struct {
+	name             string
+	params           OtpParams
+	expectedEndpoint string
+	expectedBody     otpRequest
+	authResponse     *AuthResponse
+	sendRequestErr   error
+	resultErr        error
+}{
+	{
+		name:             "successful email otp",
+		params:           OtpParams{Email: "test@example.com"},
+		expectedEndpoint: "otp",
+		expectedBody: otpRequest{
+			OtpParams:  OtpParams{Email: "test@example.com"},
+			CreateUser: true,
+		},
+		authResponse:   &AuthResponse{Status: http.StatusOK},
+		sendRequestErr: nil,
+		resultErr:      nil,
+	},
+	{
+		name: "successful whatsapp otp with options",
+		params: OtpParams{
+			Phone:         "447700900000",
+			Channel:       "whatsapp",
+			DisableSignUp: true,
+			Options:       AuthOptions{RedirectTo: "https://example.com", CaptchaToken: "captcha123", Data: map[string]any{"name": "Test"}},
+		},
+		expectedEndpoint: "otp?redirect_to=https%3A%2F%2Fexample.com",
+		expectedBody: otpRequest{
+			OtpParams: OtpParams{
+				Phone:         "447700900000",
+				Channel:       "whatsapp",
+				DisableSignUp: true,
+				Options:       AuthOptions{RedirectTo: "https://example.com", CaptchaToken: "captcha123", Data: map[string]any{"name": "Test"}},
+			},
+			CreateUser: false,
+			Data:       map[string]any{"name": "Test"},
+			Security:   &metaSecurity{CaptchaToken: "captcha123"},
+		},
+		authResponse:   &AuthResponse{Status: http.StatusOK},
+		sendRequestErr: nil,
+		resultErr:      nil,
+	},
+	{
+		name:             "otp during cooldown",
+		params:           OtpParams{Email: "test@example.com"},
+		expectedEndpoint: "otp",
+		expectedBody: otpRequest{
+			OtpParams:  OtpParams{Email: "test@example.com"},
+			CreateUser: true,
+		},
+		authResponse: &AuthResponse{
+			Status: http.StatusTooManyRequests,
+			Data:   &ErrorResponse{Status: 429, ErrorCode: "over_email_send_rate_limit", Message: "you can only request this after 5 seconds."},
+		},
+		sendRequestErr: nil,
+		resultErr:      errors.New("retry after 5s: you can only request this after 5 seconds."),
+	},
+	{
+		name:             "failed otp with send request error",
+		params:           OtpParams{Email: "test@example.com"},
+		expectedEndpoint: "otp",
+		expectedBody: otpRequest{
+			OtpParams:  OtpParams{Email: "test@example.com"},
+			CreateUser: true,
+		},
+		authResponse:   nil,
+		sendRequestErr: errors.New("send request error"),
+		resultErr:      errors.New("send request error"),
+	},
+}
+
+func TestAuth_SignInWithOtp(t *testing.T) {
+	for _, tt := range signInWithOtpTests {
+		client := new(clientMock)
+		sut := &Auth{
+			client: client,
+		}
+
+		client.On("createAndSendRequest", http.MethodPost, tt.expectedEndpoint, tt.expectedBody, nil).
+			Return(tt.authResponse, tt.sendRequestErr)
+
+		result, err := sut.SignInWithOtp(tt.params)
+
+		if err != nil {
+			assert.Equal(t, err.Error(), tt.resultErr.Error())
+		} else {
+			assert.Equal(t, tt.resultErr, nil)
+		}
+
+		assert.Equal(t, result, tt.authResponse)
+	}
+}
+
+var signInWithIdTokenTests = []struct {
+	name           string
+	credentials    IdTokenCredentials
+	expectedBody   idTokenRequest
+	authResponse   *AuthResponse
+	sendRequestErr error
+	resultErr      error
+}{
+	{
+		name:        "successful apple sign in",
+		credentials: IdTokenCredentials{Provider: "apple", IdToken: "eyJ.apple", Nonce: "raw-nonce"},
+		expectedBody: idTokenRequest{
+			IdTokenCredentials: IdTokenCredentials{Provider: "apple", IdToken: "eyJ.apple", Nonce: "raw-nonce"},
+		},
+		authResponse: &AuthResponse{
+			Status: http.StatusOK,
+			Data:   &Authenticated{AccessToken: "cba321", User: User{ID: "abc123"}},
+		},
+		sendRequestErr: nil,
+		resultErr:      nil,
+	},
+	{
+		name: "successful google sign in with access token and captcha",
+		credentials: IdTokenCredentials{
+			Provider:    "google",
+			IdToken:     "eyJ.google",
+			AccessToken: "ya29.google",
+			Options:     AuthOptions{CaptchaToken: "captcha123"},
+		},
+		expectedBody: idTokenRequest{
+			IdTokenCredentials: IdTokenCredentials{
+				Provider:    "google",
+				IdToken:     "eyJ.google",
+				AccessToken: "ya29.google",
+				Options:     AuthOptions{CaptchaToken: "captcha123"},
+			},
+			Security: &metaSecurity{CaptchaToken: "captcha123"},
+		},
+		authResponse: &AuthResponse{
+			Status: http.StatusOK,
+			Data:   &Authenticated{AccessToken: "cba321", User: User{ID: "abc123"}},
+		},
+		sendRequestErr: nil,
+		resultErr:      nil,
+	},
+	{
+		name:        "failed id token sign in with send request error",
+		credentials: IdTokenCredentials{Provider: "apple", IdToken: "eyJ.apple"},
+		expectedBody: idTokenRequest{
+			IdTokenCredentials: IdTokenCredentials{Provider: "apple", IdToken: "eyJ.apple"},
+		},
+		authResponse:   nil,
+		sendRequestErr: errors.New("send request error"),
+		resultErr:      errors.New("send request error"),
+	},
+}
+
+func TestAuth_SignInWithIdToken(t *testing.T) {
+	for _, tt := range signInWithIdTokenTests {
+		client := new(clientMock)
+		sut := &Auth{
+			client: client,
+		}
+
+		client.On("createAndSendRequest", http.MethodPost, "token?grant_type=id_token", tt.expectedBody, &Authenticated{}).
+			Return(tt.authResponse, tt.sendRequestErr)
+
+		result, err := sut.SignInWithIdToken(tt.credentials)
+
+		if err != nil {
+			assert.Equal(t, err.Error(), tt.resultErr.Error())
+			assert.Equal(t, result, tt.authResponse)
+		} else {
+			assert.Equal(t, err, nil)
+			assert.Equal(t, result, tt.authResponse)
+		}
+	}
+}
+
+var signInAnonymouslyTests = []struct {
+	name           string
+	options        AuthOptions
+	expectedBody   anonymousRequest
+	authResponse   *AuthResponse
+	sendRequestErr error
+	resultErr      error
+}{
+	{
+		name:         "successful anonymous sign in",
+		options:      AuthOptions{},
+		expectedBody: anonymousRequest{},
+		authResponse: &AuthResponse{
+			Status: http.StatusOK,
+			Data: &Authenticated{
+				AccessToken: "cba321",
+				User:        User{ID: "abc123", IsAnonymous: true},
+			},
+		},
+		sendRequestErr: nil,
+		resultErr:      nil,
+	},
+	{
+		name:    "successful anonymous sign in with captcha and data",
+		options: AuthOptions{CaptchaToken: "captcha123", Data: map[string]any{"cart": "cart-1"}},
+		expectedBody: anonymousRequest{
+			Data:     map[string]any{"cart": "cart-1"},
+			Security: &metaSecurity{CaptchaToken: "captcha123"},
+		},
+		authResponse: &AuthResponse{
+			Status: http.StatusOK,
+			Data: &Authenticated{
+				AccessToken: "cba321",
+				User:        User{ID: "abc123", IsAnonymous: true},
+			},
+		},
+		sendRequestErr: nil,
+		resultErr:      nil,
+	},
+	{
+		name:           "failed anonymous sign in with send request error",
+		options:        AuthOptions{},
+		expectedBody:   anonymousRequest{},
+		authResponse:   nil,
+		sendRequestErr: errors.New("send request error"),
+		resultErr:      errors.New("send request error"),
+	},
+}
+
+func TestAuth_SignInAnonymously(t *testing.T) {
+	for _, tt := range signInAnonymouslyTests {
+		client := new(clientMock)
+		sut := &Auth{
+			client: client,
+		}
+
+		client.On("createAndSendRequest", http.MethodPost, "signup", tt.expectedBody, &Authenticated{}).
+			Return(tt.authResponse, tt.sendRequestErr)
+
+		result, err := sut.SignInAnonymously(tt.options)
+
+		if err != nil {
+			assert.Equal(t, err.Error(), tt.resultErr.Error())
+			assert.Equal(t, result, tt.authResponse)
+		} else {
+			assert.Equal(t, err, nil)
+			assert.Equal(t, result, tt.authResponse)
+		}
+	}
+}
+
 var signInTests = []struct {
 	name           string
 	authResponse   *AuthResponse
@@ -132,7 +431,7 @@ func TestAuth_SignIn(t *testing.T) {
 			Password: "password",
 		}
 
-		client.On("createAndSendRequest", http.MethodPost, "token?grant_type=password", creds, &Authenticated{}).
+		client.On("createAndSendRequest", http.MethodPost, "token?grant_type=password", credentialsRequest{UserCredentials: creds}, &Authenticated{}).
 			Return(tt.authResponse, tt.sendRequestErr)
 
 		result, err := sut.SignIn(creds)
@@ -201,6 +500,49 @@ func TestAuth_SignOut(t *testing.T) {
 	}
 }
 
+var signOutScopeTests = []struct {
+	name             string
+	scope            SignOutScope
+	expectedEndpoint string
+}{
+	{
+		name:             "sign out everywhere",
+		scope:            SignOutScopeGlobal,
+		expectedEndpoint: "logout?scope=global",
+	},
+	{
+		name:             "sign out this session",
+		scope:            SignOutScopeLocal,
+		expectedEndpoint: "logout?scope=local",
+	},
+	{
+		name:             "sign out other sessions",
+		scope:            SignOutScopeOthers,
+		expectedEndpoint: "logout?scope=others",
+	},
+}
+
+func TestAuth_SignOutWithScope(t *testing.T) {
+	for _, tt := range signOutScopeTests {
+		client := new(clientMock)
+		sut := &Auth{
+			client: client,
+		}
+
+		req := httptest.NewRequest(http.MethodPost, "/"+tt.expectedEndpoint, nil)
+		authResponse := &AuthResponse{Status: http.StatusNoContent}
+
+		client.On("createRequest", http.MethodPost, tt.expectedEndpoint, nil).Return(req, nil)
+		client.On("sendRequest", req, nil).Return(authResponse, nil)
+
+		result, err := sut.SignOut("abc123", tt.scope)
+
+		assert.Equal(t, err, nil)
+		assert.Equal(t, result, authResponse)
+		assert.Equal(t, req.Header.Get("Authorization"), "Bearer abc123")
+	}
+}
+
 var refreshTokenTests = []struct {
 	name           string
 	authResponse   *AuthResponse
@@ -285,7 +627,7 @@ func TestAuth_ForgottenPassword(t *testing.T) {
 		}
 
 		email := "test@example.com"
-		reqBody := map[string]string{"email": email}
+		reqBody := recoverRequest{Email: email}
 
 		client.On("createAndSendRequest", http.MethodPost, "recover", reqBody, nil).
 			Return(tt.authResponse, tt.sendRequestErr)
@@ -302,6 +644,29 @@ func TestAuth_ForgottenPassword(t *testing.T) {
 	}
 }
 
+func TestAuth_ForgottenPasswordWithOptions(t *testing.T) {
+	client := new(clientMock)
+	sut := &Auth{
+		client: client,
+	}
+	reqBody := recoverRequest{
+		Email:    "test@example.com",
+		Security: &metaSecurity{CaptchaToken: "captcha123"},
+	}
+	authResponse := &AuthResponse{Status: http.StatusOK}
+
+	client.On("createAndSendRequest", http.MethodPost, "recover?redirect_to=https%3A%2F%2Fexample.com%2Freset", reqBody, nil).
+		Return(authResponse, nil)
+
+	result, err := sut.ForgottenPassword("test@example.com", AuthOptions{
+		RedirectTo:   "https://example.com/reset",
+		CaptchaToken: "captcha123",
+	})
+
+	assert.Equal(t, err, nil)
+	assert.Equal(t, result, authResponse)
+}
+
 var resetPasswordTests = []struct {
 	name           string
 	createReqErr   error
@@ -357,3 +722,366 @@ func TestAuth_ResetPassword(t *testing.T) {
 		}
 	}
 }
+
+var getUserTests = []struct {
+	name           string
+	authResponse   *AuthResponse
+	sendRequestErr error
+	resultErr      error
+}{
+	{
+		name: "successful get user",
+		authResponse: &AuthResponse{
+			Status: http.StatusOK,
+			Data: &User{
+				ID:    "abc123",
+				Email: "test@example.com",
+			},
+		},
+		sendRequestErr: nil,
+		resultErr:      nil,
+	},
+	{
+		name:           "failed get user with send request error",
+		authResponse:   nil,
+		sendRequestErr: errors.New("send request error"),
+		resultErr:      errors.New("send request error"),
+	},
+}
+
+func TestAuth_GetUser(t *testing.T) {
+	for _, tt := range getUserTests {
+		client := new(clientMock)
+		sut := &Auth{
+			client: client,
+		}
+
+		client.On("createAndSendRequestWithToken", http.MethodGet, "user", "abc123", nil, &User{}).
+			Return(tt.authResponse, tt.sendRequestErr)
+
+		result, err := sut.GetUser("abc123")
+
+		if err != nil {
+			assert.Equal(t, err.Error(), tt.resultErr.Error())
+			assert.Equal(t, result, tt.authResponse)
+		} else {
+			assert.Equal(t, err, nil)
+			assert.Equal(t, result, tt.authResponse)
+		}
+	}
+}
+
+var updateUserTests = []struct {
+	name           string
+	authResponse   *AuthResponse
+	sendRequestErr error
+	resultErr      error
+}{
+	{
+		name: "successful update user",
+		authResponse: &AuthResponse{
+			Status: http.StatusOK,
+			Data: &User{
+				ID:           "abc123",
+				Email:        "test@example.com",
+				UserMetadata: map[string]interface{}{"name": "Test"},
+			},
+		},
+		sendRequestErr: nil,
+		resultErr:      nil,
+	},
+	{
+		name:           "failed update user with send request error",
+		authResponse:   nil,
+		sendRequestErr: errors.New("send request error"),
+		resultErr:      errors.New("send request error"),
+	},
+}
+
+func TestAuth_UpdateUser(t *testing.T) {
+	for _, tt := range updateUserTests {
+		client := new(clientMock)
+		sut := &Auth{
+			client: client,
+		}
+		attributes := UserAttributes{
+			Phone: "447700900000",
+			Data:  map[string]any{"name": "Test"},
+		}
+
+		client.On("createAndSendRequestWithToken", http.MethodPut, "user", "abc123", attributes, &User{}).
+			Return(tt.authResponse, tt.sendRequestErr)
+
+		result, err := sut.UpdateUser("abc123", attributes)
+
+		if err != nil {
+			assert.Equal(t, err.Error(), tt.resultErr.Error())
+			assert.Equal(t, result, tt.authResponse)
+		} else {
+			assert.Equal(t, err, nil)
+			assert.Equal(t, result, tt.authResponse)
+		}
+	}
+}
+
+var changeContactTests = []struct {
+	name       string
+	attributes UserAttributes
+	change     func(sut *Auth) (*AuthResponse, error)
+}{
+	{
+		name:       "change email",
+		attributes: UserAttributes{Email: "new@example.com"},
+		change: func(sut *Auth) (*AuthResponse, error) {
+			return sut.ChangeEmail("abc123", "new@example.com")
+		},
+	},
+	{
+		name:       "change phone",
+		attributes: UserAttributes{Phone: "447700900001"},
+		change: func(sut *Auth) (*AuthResponse, error) {
+			return sut.ChangePhone("abc123", "447700900001")
+		},
+	},
+}
+
+func TestAuth_ChangeEmailAndPhone(t *testing.T) {
+	for _, tt := range changeContactTests {
+		client := new(clientMock)
+		sut := &Auth{
+			client: client,
+		}
+		authResponse := &AuthResponse{
+			Status: http.StatusOK,
+			Data:   &User{ID: "abc123", NewEmail: tt.attributes.Email, NewPhone: tt.attributes.Phone},
+		}
+
+		client.On("createAndSendRequestWithToken", http.MethodPut, "user", "abc123", tt.attributes, &User{}).
+			Return(authResponse, nil)
+
+		result, err := tt.change(sut)
+
+		assert.Equal(t, err, nil)
+		assert.Equal(t, result, authResponse)
+	}
+}
+
+var verifyOtpTests = []struct {
+	name           string
+	authResponse   *AuthResponse
+	sendRequestErr error
+	resultErr      error
+}{
+	{
+		name: "successful verify",
+		authResponse: &AuthResponse{
+			Status: http.StatusOK,
+			Data: &Verified{
+				Authenticated: Authenticated{AccessToken: "cba321"},
+			},
+		},
+		sendRequestErr: nil,
+		resultErr:      nil,
+	},
+	{
+		name:           "failed verify with send request error",
+		authResponse:   nil,
+		sendRequestErr: errors.New("send request error"),
+		resultErr:      errors.New("send request error"),
+	},
+}
+
+func TestAuth_VerifyOtp(t *testing.T) {
+	for _, tt := range verifyOtpTests {
+		client := new(clientMock)
+		sut := &Auth{
+			client: client,
+		}
+		params := VerifyOtpParams{
+			Type:  OtpTypeEmailChange,
+			Email: "new@example.com",
+			Token: "123456",
+		}
+
+		client.On("createAndSendRequest", http.MethodPost, "verify", params, &Verified{}).
+			Return(tt.authResponse, tt.sendRequestErr)
+
+		result, err := sut.VerifyOtp(params)
+
+		if err != nil {
+			assert.Equal(t, err.Error(), tt.resultErr.Error())
+			assert.Equal(t, result, tt.authResponse)
+		} else {
+			assert.Equal(t, err, nil)
+			assert.Equal(t, result, tt.authResponse)
+		}
+	}
+}
+
+func TestVerified_AwaitingOtherConfirmation(t *testing.T) {
+	partial := &Verified{Message: "Confirmation link accepted. Please proceed to confirm link sent to the other email"}
+	complete := &Verified{Authenticated: Authenticated{AccessToken: "cba321"}}
+
+	assert.Equal(t, partial.AwaitingOtherConfirmation(), true)
+	assert.Equal(t, complete.AwaitingOtherConfirmation(), false)
+}
+
+var resendTests = []struct {
+	name             string
+	params           ResendParams
+	expectedEndpoint string
+	expectedBody     resendRequest
+	authResponse     *AuthResponse
+	sendRequestErr   error
+	resultErr        error
+}{
+	{
+		name:             "successful resend",
+		params:           ResendParams{Type: OtpTypeEmailChange, Email: "new@example.com"},
+		expectedEndpoint: "resend",
+		expectedBody: resendRequest{
+			ResendParams: ResendParams{Type: OtpTypeEmailChange, Email: "new@example.com"},
+		},
+		authResponse:   &AuthResponse{Status: http.StatusOK},
+		sendRequestErr: nil,
+		resultErr:      nil,
+	},
+	{
+		name: "successful resend with redirect and captcha",
+		params: ResendParams{
+			Type:    OtpTypeSignup,
+			Email:   "test@example.com",
+			Options: AuthOptions{RedirectTo: "https://example.com/welcome?from=email", CaptchaToken: "captcha123"},
+		},
+		expectedEndpoint: "resend?redirect_to=https%3A%2F%2Fexample.com%2Fwelcome%3Ffrom%3Demail",
+		expectedBody: resendRequest{
+			ResendParams: ResendParams{
+				Type:    OtpTypeSignup,
+				Email:   "test@example.com",
+				Options: AuthOptions{RedirectTo: "https://example.com/welcome?from=email", CaptchaToken: "captcha123"},
+			},
+			Security: &metaSecurity{CaptchaToken: "captcha123"},
+		},
+		authResponse:   &AuthResponse{Status: http.StatusOK},
+		sendRequestErr: nil,
+		resultErr:      nil,
+	},
+	{
+		name:             "resend during cooldown",
+		params:           ResendParams{Type: OtpTypeSMS, Phone: "447700900000"},
+		expectedEndpoint: "resend",
+		expectedBody: resendRequest{
+			ResendParams: ResendParams{Type: OtpTypeSMS, Phone: "447700900000"},
+		},
+		authResponse: &AuthResponse{
+			Status: http.StatusTooManyRequests,
+			Data: &ErrorResponse{
+				Status:    429,
+				ErrorCode: "over_sms_send_rate_limit",
+				Message:   "For security purposes, you can only request this after 37 seconds.",
+			},
+		},
+		sendRequestErr: nil,
+		resultErr:      errors.New("retry after 37s: For security purposes, you can only request this after 37 seconds."),
+	},
+	{
+		name:             "failed resend with send request error",
+		params:           ResendParams{Type: OtpTypeEmailChange, Email: "new@example.com"},
+		expectedEndpoint: "resend",
+		expectedBody: resendRequest{
+			ResendParams: ResendParams{Type: OtpTypeEmailChange, Email: "new@example.com"},
+		},
+		authResponse:   nil,
+		sendRequestErr: errors.New("send request error"),
+		resultErr:      errors.New("send request error"),
+	},
+}
+
+func TestAuth_Resend(t *testing.T) {
+	for _, tt := range resendTests {
+		client := new(clientMock)
+		sut := &Auth{
+			client: client,
+		}
+
+		client.On("createAndSendRequest", http.MethodPost, tt.expectedEndpoint, tt.expectedBody, nil).
+			Return(tt.authResponse, tt.sendRequestErr)
+
+		result, err := sut.Resend(tt.params)
+
+		if err != nil {
+			assert.Equal(t, err.Error(), tt.resultErr.Error())
+		} else {
+			assert.Equal(t, tt.resultErr, nil)
+		}
+
+		assert.Equal(t, result, tt.authResponse)
+	}
+}
+
+func TestAuthOptions_Endpoint(t *testing.T) {
+	options := AuthOptions{RedirectTo: "https://example.com"}
+
+	assert.Equal(t, options.endpoint("signup"), "signup?redirect_to=https%3A%2F%2Fexample.com")
+	assert.Equal(t, options.endpoint("token?grant_type=password"), "token?grant_type=password&redirect_to=https%3A%2F%2Fexample.com")
+	assert.Equal(t, AuthOptions{}.endpoint("signup"), "signup")
+}
+
+var reauthenticateTests = []struct {
+	name           string
+	authResponse   *AuthResponse
+	sendRequestErr error
+	resultErr      error
+}{
+	{
+		name:           "successful reauthenticate",
+		authResponse:   &AuthResponse{Status: http.StatusOK},
+		sendRequestErr: nil,
+		resultErr:      nil,
+	},
+	{
+		name:           "failed reauthenticate with send request error",
+		authResponse:   nil,
+		sendRequestErr: errors.New("send request error"),
+		resultErr:      errors.New("send request error"),
+	},
+}
+
+func TestAuth_Reauthenticate(t *testing.T) {
+	for _, tt := range reauthenticateTests {
+		client := new(clientMock)
+		sut := &Auth{
+			client: client,
+		}
+
+		client.On("createAndSendRequestWithToken", http.MethodGet, "reauthenticate", "abc123", nil, nil).
+			Return(tt.authResponse, tt.sendRequestErr)
+
+		result, err := sut.Reauthenticate("abc123")
+
+		if err != nil {
+			assert.Equal(t, err.Error(), tt.resultErr.Error())
+			assert.Equal(t, result, tt.authResponse)
+		} else {
+			assert.Equal(t, err, nil)
+			assert.Equal(t, result, tt.authResponse)
+		}
+	}
+}
+
+func TestAuth_UpdatePassword(t *testing.T) {
+	client := new(clientMock)
+	sut := &Auth{
+		client: client,
+	}
+	attributes := UserAttributes{Password: "newPassword", Nonce: "654321"}
+	authResponse := &AuthResponse{Status: http.StatusOK, Data: &User{ID: "abc123"}}
+
+	client.On("createAndSendRequestWithToken", http.MethodPut, "user", "abc123", attributes, &User{}).
+		Return(authResponse, nil)
+
+	result, err := sut.UpdatePassword("abc123", "newPassword", "654321")
+
+	assert.Equal(t, err, nil)
+	assert.Equal(t, result, authResponse)
+}
@@ -0,0 +1,148 @@
+package supauth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+var (
+	ErrMissingJWTSecret          = errors.New("jwt secret is empty")
+	ErrMalformedToken            = errors.New("malformed access token")
+	ErrUnsupportedTokenAlgorithm = errors.New("unsupported access token algorithm")
+	ErrInvalidTokenSignature     = errors.New("invalid access token signature")
+	ErrTokenExpired              = errors.New("access token has expired")
+)
+
+// Claims are the claims GoTrue puts in an access token.
+type Claims struct {
+	Subject      string         `json:"sub"`
+	Audience     string         `json:"aud"`
+	Issuer       string         `json:"iss"`
+	ExpiresAt    int64          `json:"exp"`
+	IssuedAt     int64          `json:"iat"`
+	Email        string         `json:"email"`
+	Phone        string         `json:"phone"`
+	Role         string         `json:"role"`
+	AAL          string         `json:"aal"`
+	AMR          []AMREntry     `json:"amr"`
+	SessionID    string         `json:"session_id"`
+	IsAnonymous  bool           `json:"is_anonymous"`
+	AppMetadata  AppMetadata    `json:"app_metadata"`
+	UserMetadata map[string]any `json:"user_metadata"`
+	// Extra holds custom claims, such as those added by an access token hook.
+	Extra map[string]any `json:"-"`
+}
+
+// AMREntry is one way the user authenticated during the session.
+type AMREntry struct {
+	Method    string `json:"method"`
+	Timestamp int64  `json:"timestamp"`
+}
+
+// ParseClaims decodes an access token's claims without verifying its
+// signature. Only use it on tokens GoTrue has just returned, or ones that
+// have already been verified.
+func ParseClaims(accessToken string) (*Claims, error) {
+	parts := strings.Split(accessToken, ".")
+	if len(parts) != 3 {
+		return nil, ErrMalformedToken
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrMalformedToken, err)
+	}
+
+	claims := &Claims{}
+
+	err = json.Unmarshal(payload, claims)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrMalformedToken, err)
+	}
+
+	return claims, nil
+}
+
+// VerifyToken checks an HS256 access token against the project's JWT secret
+// and that it has not expired at now, then returns its claims. Projects using
+// asymmetric signing keys must verify tokens against their JWKS instead.
+func VerifyToken(accessToken string, jwtSecret []byte, now time.Time) (*Claims, error) {
+	if len(jwtSecret) == 0 {
+		return nil, ErrMissingJWTSecret
+	}
+
+	parts := strings.Split(accessToken, ".")
+	if len(parts) != 3 {
+		return nil, ErrMalformedToken
+	}
+
+	var header struct {
+		Algorithm string `json:"alg"`
+	}
+
+	headerJSON, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err == nil {
+		err = json.Unmarshal(headerJSON, &header)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrMalformedToken, err)
+	}
+
+	if header.Algorithm != "HS256" {
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedTokenAlgorithm, header.Algorithm)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrMalformedToken, err)
+	}
+
+	mac := hmac.New(sha256.New, jwtSecret)
+	mac.Write([]byte(parts[0] + "." + parts[1]))
+
+	if !hmac.Equal(signature, mac.Sum(nil)) {
+		return nil, ErrInvalidTokenSignature
+	}
+
+	claims, err := ParseClaims(accessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	if !now.Before(time.Unix(claims.ExpiresAt, 0)) {
+		return nil, ErrTokenExpired
+	}
+
+	return claims, nil
+}
+
+func (c *Claims) UnmarshalJSON(data []byte) error {
+	type claims Claims
+	decoded := claims{}
+
+	extra, err := unmarshalWithExtra[any](data, &decoded)
+	if err != nil {
+		return err
+	}
+
+	*c = Claims(decoded)
+	c.Extra = extra
+
+	return nil
+}
+
+func (c *Claims) HasRole(role string) bool {
+	return slices.Contains(c.AppMetadata.Roles(), role)
+}
+
+func (c *Claims) HasPermission(permission string) bool {
+	return slices.Contains(c.AppMetadata.Permissions(), permission)
+}
@@ -0,0 +1,177 @@
+package supauth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"github.com/go-playground/assert/v2"
+	"testing"
+	"time"
+)
+
+const accessTokenClaimsJSON = `{
+	"sub": "abc123",
+	"aud": "authenticated",
+	"iss": "https://project.supabase.co/auth/v1",
+	"exp": 1714560000,
+	"iat": 1714556400,
+	"email": "test@example.com",
+	"phone": "",
+	"role": "authenticated",
+	"aal": "aal2",
+	"amr": [{"method": "password", "timestamp": 1714556000}, {"method": "totp", "timestamp": 1714556400}],
+	"session_id": "session-1",
+	"is_anonymous": false,
+	"app_metadata": {"provider": "email", "providers": ["email"], "roles": ["admin"], "permissions": ["invoices:read"]},
+	"user_metadata": {"name": "Test"},
+	"tenant_id": "acme"
+}`
+
+func testToken(payload string) string {
+	return "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9." + base64.RawURLEncoding.EncodeToString([]byte(payload)) + ".c2ln"
+}
+
+func signedTestToken(header, payload string, secret []byte) string {
+	unsigned := base64.RawURLEncoding.EncodeToString([]byte(header)) + "." + base64.RawURLEncoding.EncodeToString([]byte(payload))
+
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(unsigned))
+
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func TestParseClaims(t *testing.T) {
+	claims, err := ParseClaims(testToken(accessTokenClaimsJSON))
+
+	assert.Equal(t, err, nil)
+	assert.Equal(t, claims.Subject, "abc123")
+	assert.Equal(t, claims.Audience, "authenticated")
+	assert.Equal(t, claims.ExpiresAt, int64(1714560000))
+	assert.Equal(t, claims.AAL, "aal2")
+	assert.Equal(t, claims.AMR, []AMREntry{{Method: "password", Timestamp: 1714556000}, {Method: "totp", Timestamp: 1714556400}})
+	assert.Equal(t, claims.SessionID, "session-1")
+	assert.Equal(t, claims.AppMetadata.Provider, "email")
+	assert.Equal(t, claims.UserMetadata, map[string]any{"name": "Test"})
+	assert.Equal(t, claims.Extra, map[string]any{"tenant_id": "acme"})
+	assert.Equal(t, claims.HasRole("admin"), true)
+	assert.Equal(t, claims.HasRole("billing"), false)
+	assert.Equal(t, claims.HasPermission("invoices:read"), true)
+	assert.Equal(t, claims.HasPermission("invoices:write"), false)
+}
+
+var parseClaimsErrorTests = []struct {
+	name          string
+	token         string
+	expectedError error
+}{
+	{
+		name:          "not a jwt",
+		token:         "abc",
+		expectedError: errors.New("malformed access token"),
+	},
+	{
+		name:          "invalid base64",
+		token:         "a.!!.c",
+		expectedError: errors.New("malformed access token: illegal base64 data at input byte 0"),
+	},
+	{
+		name:          "invalid claims",
+		token:         testToken(`{"sub": 1}`),
+		expectedError: nil,
+	},
+}
+
+func TestParseClaimsErrors(t *testing.T) {
+	for _, tt := range parseClaimsErrorTests {
+		claims, err := ParseClaims(tt.token)
+
+		assert.Equal(t, claims, nil)
+		assert.Equal(t, errors.Is(err, ErrMalformedToken), true)
+
+		if tt.expectedError != nil {
+			assert.Equal(t, err.Error(), tt.expectedError.Error())
+		}
+	}
+}
+
+var testJWTSecret = []byte("super-secret-jwt-token-with-at-least-32-characters")
+
+var verifyTokenTests = []struct {
+	name          string
+	token         string
+	now           time.Time
+	expectedError error
+}{
+	{
+		name:          "valid token",
+		token:         signedTestToken(`{"alg":"HS256","typ":"JWT"}`, accessTokenClaimsJSON, testJWTSecret),
+		now:           time.Unix(1714556400, 0),
+		expectedError: nil,
+	},
+	{
+		name:          "expired token",
+		token:         signedTestToken(`{"alg":"HS256","typ":"JWT"}`, accessTokenClaimsJSON, testJWTSecret),
+		now:           time.Unix(1714560000, 0),
+		expectedError: ErrTokenExpired,
+	},
+	{
+		name:          "wrong secret",
+		token:         signedTestToken(`{"alg":"HS256","typ":"JWT"}`, accessTokenClaimsJSON, []byte("other")),
+		now:           time.Unix(1714556400, 0),
+		expectedError: ErrInvalidTokenSignature,
+	},
+	{
+		name:          "unsigned token",
+		token:         signedTestToken(`{"alg":"none"}`, accessTokenClaimsJSON, testJWTSecret),
+		now:           time.Unix(1714556400, 0),
+		expectedError: errors.New("unsupported access token algorithm: \"none\""),
+	},
+	{
+		name:          "not a jwt",
+		token:         "abc",
+		now:           time.Unix(1714556400, 0),
+		expectedError: ErrMalformedToken,
+	},
+	{
+		name:          "invalid header",
+		token:         "!!.e30.c2ln",
+		now:           time.Unix(1714556400, 0),
+		expectedError: errors.New("malformed access token: illegal base64 data at input byte 0"),
+	},
+	{
+		name:          "invalid signature encoding",
+		token:         "eyJhbGciOiJIUzI1NiJ9.e30.!!",
+		now:           time.Unix(1714556400, 0),
+		expectedError: errors.New("malformed access token: illegal base64 data at input byte 0"),
+	},
+	{
+		name:          "invalid claims",
+		token:         signedTestToken(`{"alg":"HS256"}`, `{`, testJWTSecret),
+		now:           time.Unix(1714556400, 0),
+		expectedError: errors.New("malformed access token: unexpected end of JSON input"),
+	},
+}
+
+func TestVerifyTokenEmptySecret(t *testing.T) {
+	for _, secret := range [][]byte{nil, {}} {
+		claims, err := VerifyToken(signedTestToken(`{"alg":"HS256"}`, accessTokenClaimsJSON, secret), secret, time.Unix(1714556400, 0))
+
+		assert.Equal(t, claims, nil)
+		assert.Equal(t, err, ErrMissingJWTSecret)
+	}
+}
+
+func TestVerifyToken(t *testing.T) {
+	for _, tt := range verifyTokenTests {
+		claims, err := VerifyToken(tt.token, testJWTSecret, tt.now)
+
+		if tt.expectedError != nil {
+			assert.Equal(t, claims, nil)
+			assert.Equal(t, err.Error(), tt.expectedError.Error())
+		} else {
+			assert.Equal(t, err, nil)
+			assert.Equal(t, claims.Subject, "abc123")
+		}
+	}
+}
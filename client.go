@@ -7,6 +7,8 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"regexp"
+	"strconv"
 	"time"
 )
 
@@ -18,6 +20,7 @@ type httpClientInterface interface {
 
 type clientInterface interface {
 	createAndSendRequest(method, endpoint string, data, successValue any) (*AuthResponse, error)
+	createAndSendRequestWithToken(method, endpoint, token string, data, successValue any) (*AuthResponse, error)
 	createRequest(method, endpoint string, data any) (*http.Request, error)
 	sendRequest(req *http.Request, successValue any) (*AuthResponse, error)
 }
@@ -25,6 +28,9 @@ type clientInterface interface {
 type AuthResponse struct {
 	Status int `json:"status"`
 	Data   any `json:"data"`
+	// RetryAfter is the wait an error response asked for in its Retry-After
+	// header, or zero if it had none.
+	RetryAfter time.Duration `json:"-"`
 }
 
 type ErrorResponse struct {
@@ -33,6 +39,65 @@ type ErrorResponse struct {
 	Message   string `json:"msg"`
 }
 
+func (e *ErrorResponse) Error() string {
+	if e.Status == 0 {
+		return fmt.Sprintf("%s: %s", e.ErrorCode, e.Message)
+	}
+
+	return fmt.Sprintf("%d %s: %s", e.Status, e.ErrorCode, e.Message)
+}
+
+// CooldownError is returned when GoTrue refuses to send another email or SMS
+// until RetryAfter has passed.
+type CooldownError struct {
+	RetryAfter    time.Duration
+	ErrorResponse *ErrorResponse
+}
+
+func (e *CooldownError) Error() string {
+	return fmt.Sprintf("retry after %s: %s", e.RetryAfter, e.ErrorResponse.Message)
+}
+
+func (e *CooldownError) Unwrap() error {
+	return e.ErrorResponse
+}
+
+var cooldownPattern = regexp.MustCompile(`after (\d+) seconds?`)
+
+func cooldownError(response *AuthResponse) error {
+	errorResponse, ok := response.Data.(*ErrorResponse)
+	if !ok || response.Status != http.StatusTooManyRequests {
+		return nil
+	}
+
+	match := cooldownPattern.FindStringSubmatch(errorResponse.Message)
+	if match == nil {
+		return nil
+	}
+
+	seconds, _ := strconv.Atoi(match[1])
+
+	return &CooldownError{
+		RetryAfter:    time.Duration(seconds) * time.Second,
+		ErrorResponse: errorResponse,
+	}
+}
+
+// retryAfter parses a Retry-After header, given either in seconds or as an
+// HTTP date.
+func retryAfter(header string, now time.Time) time.Duration {
+	if seconds, err := strconv.Atoi(header); err == nil {
+		return time.Duration(max(seconds, 0)) * time.Second
+	}
+
+	date, err := http.ParseTime(header)
+	if err != nil {
+		return 0
+	}
+
+	return max(date.Sub(now), 0)
+}
+
 type client struct {
 	BaseUrl    string
 	ApiKey     string
@@ -60,6 +125,17 @@ func (c *client) createAndSendRequest(method, endpoint string, data, successValu
 	return c.sendRequest(req, successValue)
 }
 
+func (c *client) createAndSendRequestWithToken(method, endpoint, token string, data, successValue any) (*AuthResponse, error) {
+	req, err := c.createRequest(method, endpoint, data)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+
+	return c.sendRequest(req, successValue)
+}
+
 func (c *client) createRequest(method, endpoint string, data any) (*http.Request, error) {
 	if c.BaseUrl == "" {
 		return nil, errors.New("supabase api url is empty")
@@ -111,6 +187,7 @@ func (c *client) sendRequest(req *http.Request, successValue any) (*AuthResponse
 		}
 
 		response.Data = errorValue
+		response.RetryAfter = retryAfter(res.Header.Get("Retry-After"), time.Now())
 
 		return &response, nil
 	}
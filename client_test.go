@@ -88,6 +88,63 @@ func TestCreateAndSendRequest(t *testing.T) {
 	}
 }
 
+var createAndSendRequestWithTokenTests = []struct {
+	name         string
+	url          string
+	statusCode   int
+	jsonResponse string
+	expectedData any
+	expectedErr  error
+}{
+	{
+		name:         "successfully creates and sends request with token",
+		url:          "https://test.supabase.co/auth/v1",
+		statusCode:   http.StatusOK,
+		jsonResponse: `{"foo": "bar"}`,
+		expectedData: &map[string]any{"foo": "bar"},
+		expectedErr:  nil,
+	},
+	{
+		name:         "fails to create and send request with token",
+		url:          "",
+		statusCode:   http.StatusBadRequest,
+		jsonResponse: `{"foo": "bar"}`,
+		expectedData: nil,
+		expectedErr:  errors.New("supabase api url is empty"),
+	},
+}
+
+func TestCreateAndSendRequestWithToken(t *testing.T) {
+	for _, tt := range createAndSendRequestWithTokenTests {
+		httpClient := new(HttpClientMock)
+		sut := client{
+			BaseUrl:    tt.url,
+			HttpClient: httpClient,
+		}
+
+		var successValue = map[string]any{}
+
+		w := httptest.NewRecorder()
+		w.WriteHeader(tt.statusCode)
+		w.Write([]byte(tt.jsonResponse))
+
+		httpClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+			return req.Header.Get("Authorization") == "Bearer abc123"
+		})).Return(w.Result(), nil)
+
+		result, err := sut.createAndSendRequestWithToken(http.MethodPost, "test", "abc123", nil, successValue)
+
+		if err != nil {
+			assert.Equal(t, err.Error(), tt.expectedErr.Error())
+			assert.Equal(t, result, tt.expectedData)
+		} else {
+			assert.Equal(t, err, nil)
+			assert.Equal(t, result.Status, tt.statusCode)
+			assert.Equal(t, result.Data, tt.expectedData)
+		}
+	}
+}
+
 var createRequestTests = []struct {
 	name        string
 	url         string
@@ -257,3 +314,121 @@ func TestSendRequest(t *testing.T) {
 		}
 	}
 }
+
+func TestErrorResponse_Error(t *testing.T) {
+	err := &ErrorResponse{
+		Status:    422,
+		ErrorCode: "email_exists",
+		Message:   "A user with this email address has already been registered",
+	}
+
+	assert.Equal(t, err.Error(), "422 email_exists: A user with this email address has already been registered")
+
+	err = &ErrorResponse{ErrorCode: "access_denied", Message: "User cancelled"}
+
+	assert.Equal(t, err.Error(), "access_denied: User cancelled")
+}
+
+var cooldownErrorTests = []struct {
+	name               string
+	authResponse       *AuthResponse
+	expectedRetryAfter time.Duration
+	expectErr          bool
+}{
+	{
+		name: "email cooldown",
+		authResponse: &AuthResponse{
+			Status: http.StatusTooManyRequests,
+			Data: &ErrorResponse{
+				Status:    429,
+				ErrorCode: "over_email_send_rate_limit",
+				Message:   "For security purposes, you can only request this after 1 second.",
+			},
+		},
+		expectedRetryAfter: time.Second,
+		expectErr:          true,
+	},
+	{
+		name: "rate limited without a wait time",
+		authResponse: &AuthResponse{
+			Status: http.StatusTooManyRequests,
+			Data:   &ErrorResponse{Status: 429, ErrorCode: "over_request_rate_limit", Message: "Request rate limit reached"},
+		},
+		expectErr: false,
+	},
+	{
+		name: "other error",
+		authResponse: &AuthResponse{
+			Status: http.StatusBadRequest,
+			Data:   &ErrorResponse{Status: 400, Message: "only request this after 10 seconds"},
+		},
+		expectErr: false,
+	},
+	{
+		name:         "success",
+		authResponse: &AuthResponse{Status: http.StatusOK},
+		expectErr:    false,
+	},
+}
+
+func TestCooldownError(t *testing.T) {
+	for _, tt := range cooldownErrorTests {
+		err := cooldownError(tt.authResponse)
+
+		if !tt.expectErr {
+			assert.Equal(t, err, nil)
+			continue
+		}
+
+		var cooldown *CooldownError
+		assert.Equal(t, errors.As(err, &cooldown), true)
+		assert.Equal(t, cooldown.RetryAfter, tt.expectedRetryAfter)
+
+		var errorResponse *ErrorResponse
+		assert.Equal(t, errors.As(err, &errorResponse), true)
+		assert.Equal(t, errorResponse, tt.authResponse.Data)
+	}
+}
+
+var retryAfterTests = []struct {
+	header   string
+	expected time.Duration
+}{
+	{header: "5", expected: 5 * time.Second},
+	{header: "-3", expected: 0},
+	{header: "Wed, 01 May 2024 10:00:10 GMT", expected: 10 * time.Second},
+	{header: "Wed, 01 May 2024 09:59:00 GMT", expected: 0},
+	{header: "", expected: 0},
+	{header: "soon", expected: 0},
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	for _, tt := range retryAfterTests {
+		assert.Equal(t, retryAfter(tt.header, now), tt.expected)
+	}
+}
+
+func TestSendRequestRetryAfter(t *testing.T) {
+	httpClient := new(HttpClientMock)
+	sut := client{
+		BaseUrl:    "http://localhost",
+		HttpClient: httpClient,
+	}
+
+	req, _ := sut.createRequest(http.MethodGet, "test", nil)
+
+	w := httptest.NewRecorder()
+	w.Header().Set("Retry-After", "30")
+	w.WriteHeader(http.StatusTooManyRequests)
+	w.Write([]byte(`{"code": 429, "error_code": "over_request_rate_limit", "msg": "Request rate limit reached"}`))
+
+	httpClient.On("Do", mock.Anything).Return(w.Result(), nil)
+
+	response, err := sut.sendRequest(req, nil)
+
+	assert.Equal(t, err, nil)
+	assert.Equal(t, response.Status, http.StatusTooManyRequests)
+	assert.Equal(t, response.RetryAfter, 30*time.Second)
+}
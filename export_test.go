@@ -0,0 +1,200 @@
+package supauth
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"github.com/go-playground/assert/v2"
+	"net/http"
+	"testing"
+)
+
+type failingWriter struct{}
+
+func (f *failingWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("write error")
+}
+
+func exportTestAdmin(pages ...*AuthResponse) *adminMock {
+	admin := new(adminMock)
+
+	for i, page := range pages {
+		admin.On("ListUsers", i+1, 2).Return(page, nil)
+	}
+
+	return admin
+}
+
+func TestExporter_ExportJSONLines(t *testing.T) {
+	admin := exportTestAdmin(
+		&AuthResponse{Status: http.StatusOK, Data: &UserList{Users: []User{
+			{ID: "user-1", Email: "one@example.com", Extra: map[string]json.RawMessage{"custom_field": json.RawMessage(`"kept"`)}},
+			{ID: "user-2", Email: "two@example.com"},
+		}}},
+		&AuthResponse{Status: http.StatusOK, Data: &UserList{Users: []User{
+			{ID: "user-3", Email: "three@example.com"},
+		}}},
+	)
+
+	var out bytes.Buffer
+	count, err := NewExporter(admin, ExportOptions{PerPage: 2}).Export(&out)
+
+	assert.Equal(t, err, nil)
+	assert.Equal(t, count, 3)
+	assert.Equal(t, out.String(), `{"app_metadata":{"provider":"","providers":null},"aud":"","banned_until":null,"confirmation_sent_at":null,"confirmed_at":null,"created_at":null,"custom_field":"kept","deleted_at":null,"email":"one@example.com","email_change_sent_at":null,"email_confirmed_at":null,"factors":null,"id":"user-1","identities":null,"invited_at":null,"is_anonymous":false,"is_sso_user":false,"last_sign_in_at":null,"new_email":"","new_phone":"","phone":"","phone_change_sent_at":null,"phone_confirmed_at":null,"role":"","updated_at":null,"user_metadata":null}
+{"app_metadata":{"provider":"","providers":null},"aud":"","banned_until":null,"confirmation_sent_at":null,"confirmed_at":null,"created_at":null,"deleted_at":null,"email":"two@example.com","email_change_sent_at":null,"email_confirmed_at":null,"factors":null,"id":"user-2","identities":null,"invited_at":null,"is_anonymous":false,"is_sso_user":false,"last_sign_in_at":null,"new_email":"","new_phone":"","phone":"","phone_change_sent_at":null,"phone_confirmed_at":null,"role":"","updated_at":null,"user_metadata":null}
+{"app_metadata":{"provider":"","providers":null},"aud":"","banned_until":null,"confirmation_sent_at":null,"confirmed_at":null,"created_at":null,"deleted_at":null,"email":"three@example.com","email_change_sent_at":null,"email_confirmed_at":null,"factors":null,"id":"user-3","identities":null,"invited_at":null,"is_anonymous":false,"is_sso_user":false,"last_sign_in_at":null,"new_email":"","new_phone":"","phone":"","phone_change_sent_at":null,"phone_confirmed_at":null,"role":"","updated_at":null,"user_metadata":null}
+`)
+	admin.AssertNumberOfCalls(t, "ListUsers", 2)
+}
+
+func TestExporter_ExportJSONLinesColumns(t *testing.T) {
+	admin := exportTestAdmin(
+		&AuthResponse{Status: http.StatusOK, Data: &UserList{Users: []User{
+			{ID: "user-1", AppMetadata: AppMetadata{Provider: "google"}},
+		}}},
+	)
+
+	var out bytes.Buffer
+	count, err := NewExporter(admin, ExportOptions{
+		Format:  ExportFormatJSONLines,
+		Columns: []string{"id", "app_metadata.provider", "email.domain"},
+		PerPage: 2,
+	}).Export(&out)
+
+	assert.Equal(t, err, nil)
+	assert.Equal(t, count, 1)
+	assert.Equal(t, out.String(), `{"app_metadata.provider":"google","email.domain":null,"id":"user-1"}
+`)
+}
+
+func TestExporter_ExportCSV(t *testing.T) {
+	admin := exportTestAdmin(
+		&AuthResponse{Status: http.StatusOK, Data: &UserList{Users: []User{
+			{
+				ID:          "user-1",
+				Email:       "one@example.com",
+				AppMetadata: AppMetadata{Provider: "email", Extra: map[string]any{"level": float64(3)}},
+				Factors:     []Factor{{ID: "factor-1", FactorType: FactorTypeTOTP, Status: "verified"}},
+			},
+		}}},
+	)
+
+	var out bytes.Buffer
+	count, err := NewExporter(admin, ExportOptions{
+		Format:  ExportFormatCSV,
+		Columns: []string{"id", "email", "phone", "is_anonymous", "app_metadata.level", "factors"},
+		PerPage: 2,
+	}).Export(&out)
+
+	assert.Equal(t, err, nil)
+	assert.Equal(t, count, 1)
+	assert.Equal(t, out.String(), "id,email,phone,is_anonymous,app_metadata.level,factors\n"+
+		`user-1,one@example.com,,false,3,"[{""created_at"":null,""factor_type"":""totp"",""friendly_name"":"""",""id"":""factor-1"",""status"":""verified"",""updated_at"":null}]"`+"\n")
+}
+
+func TestExporter_ExportCSVDefaultColumns(t *testing.T) {
+	admin := exportTestAdmin(&AuthResponse{Status: http.StatusOK, Data: &UserList{}})
+
+	var out bytes.Buffer
+	count, err := NewExporter(admin, ExportOptions{Format: ExportFormatCSV, PerPage: 2}).Export(&out)
+
+	assert.Equal(t, err, nil)
+	assert.Equal(t, count, 0)
+	assert.Equal(t, out.String(), "id,email,phone,role,app_metadata.provider,email_confirmed_at,phone_confirmed_at,last_sign_in_at,created_at,updated_at\n")
+}
+
+var exportErrorTests = []struct {
+	name          string
+	format        ExportFormat
+	writer        *failingWriter
+	authResponse  *AuthResponse
+	sendErr       error
+	expectedCount int
+	expectedErr   error
+}{
+	{
+		name:          "unsupported format",
+		format:        "xml",
+		expectedCount: 0,
+		expectedErr:   errors.New("unsupported export format: \"xml\""),
+	},
+	{
+		name:          "send request error",
+		format:        ExportFormatJSONLines,
+		authResponse:  nil,
+		sendErr:       errors.New("send request error"),
+		expectedCount: 0,
+		expectedErr:   errors.New("send request error"),
+	},
+	{
+		name:          "error response",
+		format:        ExportFormatJSONLines,
+		authResponse:  &AuthResponse{Status: http.StatusForbidden, Data: &ErrorResponse{Status: 403, ErrorCode: "not_admin", Message: "User not allowed"}},
+		expectedCount: 0,
+		expectedErr:   errors.New("403 not_admin: User not allowed"),
+	},
+	{
+		name:          "json lines write error",
+		format:        ExportFormatJSONLines,
+		writer:        &failingWriter{},
+		authResponse:  &AuthResponse{Status: http.StatusOK, Data: &UserList{Users: []User{{ID: "user-1"}}}},
+		expectedCount: 0,
+		expectedErr:   errors.New("write error"),
+	},
+	{
+		name:          "csv flush error",
+		format:        ExportFormatCSV,
+		writer:        &failingWriter{},
+		authResponse:  &AuthResponse{Status: http.StatusOK, Data: &UserList{Users: []User{{ID: "user-1"}}}},
+		expectedCount: 1,
+		expectedErr:   errors.New("write error"),
+	},
+}
+
+func TestExporter_ExportErrors(t *testing.T) {
+	for _, tt := range exportErrorTests {
+		admin := new(adminMock)
+		admin.On("ListUsers", 1, 100).Return(tt.authResponse, tt.sendErr)
+
+		var out bytes.Buffer
+		sut := NewExporter(admin, ExportOptions{Format: tt.format})
+
+		var count int
+		var err error
+		if tt.writer != nil {
+			count, err = sut.Export(tt.writer)
+		} else {
+			count, err = sut.Export(&out)
+		}
+
+		assert.Equal(t, count, tt.expectedCount)
+		assert.Equal(t, err.Error(), tt.expectedErr.Error())
+	}
+}
+
+func TestExporter_ExportUnencodableUser(t *testing.T) {
+	admin := new(adminMock)
+	admin.On("ListUsers", 1, 100).Return(&AuthResponse{Status: http.StatusOK, Data: &UserList{Users: []User{
+		{ID: "user-1", Extra: map[string]json.RawMessage{"custom_field": json.RawMessage(`{`)}},
+	}}}, nil)
+
+	var out bytes.Buffer
+	count, err := NewExporter(admin, ExportOptions{}).Export(&out)
+
+	assert.NotEqual(t, err, nil)
+	assert.Equal(t, count, 0)
+	assert.Equal(t, out.String(), "")
+}
+
+func TestExporter_ExportNoContent(t *testing.T) {
+	admin := new(adminMock)
+	admin.On("ListUsers", 1, 100).Return(&AuthResponse{Status: http.StatusNoContent}, nil)
+
+	var out bytes.Buffer
+	count, err := NewExporter(admin, ExportOptions{}).Export(&out)
+
+	assert.Equal(t, err, nil)
+	assert.Equal(t, count, 0)
+	assert.Equal(t, out.String(), "")
+}
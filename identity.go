@@ -0,0 +1,64 @@
+package supauth
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+type LinkIdentityParams struct {
+	// Provider is the OAuth provider to link, such as "google" or "github".
+	Provider string
+	// Scopes are extra OAuth scopes to request, separated by spaces.
+	Scopes  string
+	Options AuthOptions
+}
+
+type IdentityLink struct {
+	URL string `json:"url"`
+}
+
+// LinkIdentity returns the provider's authorisation URL for attaching an
+// OAuth identity to the signed in user. Once the user approves, GoTrue links
+// the identity and redirects to Options.RedirectTo. Manual linking must be
+// enabled on the project.
+func (a *Auth) LinkIdentity(accessToken string, params LinkIdentityParams) (*AuthResponse, error) {
+	query := url.Values{}
+	query.Set("provider", params.Provider)
+	query.Set("skip_http_redirect", "true")
+
+	if params.Scopes != "" {
+		query.Set("scopes", params.Scopes)
+	}
+
+	if params.Options.RedirectTo != "" {
+		query.Set("redirect_to", params.Options.RedirectTo)
+	}
+
+	successResponse := &IdentityLink{}
+
+	return a.client.createAndSendRequestWithToken(http.MethodGet, "user/identities/authorize?"+query.Encode(), accessToken, nil, successResponse)
+}
+
+// UnlinkIdentity removes an identity from the signed in user. identityID is
+// Identity.IdentityID. A user must keep at least one identity.
+func (a *Auth) UnlinkIdentity(accessToken, identityID string) (*AuthResponse, error) {
+	endpoint := fmt.Sprintf("user/identities/%s", url.PathEscape(identityID))
+
+	return a.client.createAndSendRequestWithToken(http.MethodDelete, endpoint, accessToken, nil, nil)
+}
+
+// GetUserIdentities fetches the signed in user and returns their identities
+// as the response's *[]Identity data.
+func (a *Auth) GetUserIdentities(accessToken string) (*AuthResponse, error) {
+	authResponse, err := a.GetUser(accessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	if user, ok := authResponse.Data.(*User); ok {
+		authResponse.Data = &user.Identities
+	}
+
+	return authResponse, nil
+}
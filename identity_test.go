@@ -0,0 +1,174 @@
+package supauth
+
+import (
+	"errors"
+	"github.com/go-playground/assert/v2"
+	"net/http"
+	"testing"
+)
+
+var linkIdentityTests = []struct {
+	name             string
+	params           LinkIdentityParams
+	expectedEndpoint string
+	authResponse     *AuthResponse
+	sendRequestErr   error
+	resultErr        error
+}{
+	{
+		name:             "successful link",
+		params:           LinkIdentityParams{Provider: "google"},
+		expectedEndpoint: "user/identities/authorize?provider=google&skip_http_redirect=true",
+		authResponse: &AuthResponse{
+			Status: http.StatusOK,
+			Data:   &IdentityLink{URL: "https://accounts.google.com/o/oauth2/auth?client_id=abc"},
+		},
+		sendRequestErr: nil,
+		resultErr:      nil,
+	},
+	{
+		name: "successful link with scopes and redirect",
+		params: LinkIdentityParams{
+			Provider: "github",
+			Scopes:   "repo read:org",
+			Options:  AuthOptions{RedirectTo: "https://app.example.com/settings"},
+		},
+		expectedEndpoint: "user/identities/authorize?provider=github&redirect_to=https%3A%2F%2Fapp.example.com%2Fsettings" +
+			"&scopes=repo+read%3Aorg&skip_http_redirect=true",
+		authResponse: &AuthResponse{
+			Status: http.StatusOK,
+			Data:   &IdentityLink{URL: "https://github.com/login/oauth/authorize?client_id=abc"},
+		},
+		sendRequestErr: nil,
+		resultErr:      nil,
+	},
+	{
+		name:             "failed link with send request error",
+		params:           LinkIdentityParams{Provider: "google"},
+		expectedEndpoint: "user/identities/authorize?provider=google&skip_http_redirect=true",
+		authResponse:     nil,
+		sendRequestErr:   errors.New("send request error"),
+		resultErr:        errors.New("send request error"),
+	},
+}
+
+func TestAuth_LinkIdentity(t *testing.T) {
+	for _, tt := range linkIdentityTests {
+		client := new(clientMock)
+		sut := &Auth{
+			client: client,
+		}
+
+		client.On("createAndSendRequestWithToken", http.MethodGet, tt.expectedEndpoint, "abc123", nil, &IdentityLink{}).
+			Return(tt.authResponse, tt.sendRequestErr)
+
+		result, err := sut.LinkIdentity("abc123", tt.params)
+
+		if err != nil {
+			assert.Equal(t, err.Error(), tt.resultErr.Error())
+			assert.Equal(t, result, tt.authResponse)
+		} else {
+			assert.Equal(t, err, nil)
+			assert.Equal(t, result, tt.authResponse)
+		}
+	}
+}
+
+var unlinkIdentityTests = []struct {
+	name           string
+	authResponse   *AuthResponse
+	sendRequestErr error
+	resultErr      error
+}{
+	{
+		name:           "successful unlink",
+		authResponse:   &AuthResponse{Status: http.StatusOK},
+		sendRequestErr: nil,
+		resultErr:      nil,
+	},
+	{
+		name:           "failed unlink with send request error",
+		authResponse:   nil,
+		sendRequestErr: errors.New("send request error"),
+		resultErr:      errors.New("send request error"),
+	},
+}
+
+func TestAuth_UnlinkIdentity(t *testing.T) {
+	for _, tt := range unlinkIdentityTests {
+		client := new(clientMock)
+		sut := &Auth{
+			client: client,
+		}
+
+		client.On("createAndSendRequestWithToken", http.MethodDelete, "user/identities/identity-1", "abc123", nil, nil).
+			Return(tt.authResponse, tt.sendRequestErr)
+
+		result, err := sut.UnlinkIdentity("abc123", "identity-1")
+
+		if err != nil {
+			assert.Equal(t, err.Error(), tt.resultErr.Error())
+			assert.Equal(t, result, tt.authResponse)
+		} else {
+			assert.Equal(t, err, nil)
+			assert.Equal(t, result, tt.authResponse)
+		}
+	}
+}
+
+var identities = []Identity{
+	{IdentityID: "identity-1", Provider: "email"},
+	{IdentityID: "identity-2", Provider: "google"},
+}
+
+var getUserIdentitiesTests = []struct {
+	name           string
+	authResponse   *AuthResponse
+	sendRequestErr error
+	expectedResult *AuthResponse
+	resultErr      error
+}{
+	{
+		name:           "successful get identities",
+		authResponse:   &AuthResponse{Status: http.StatusOK, Data: &User{ID: "abc123", Identities: identities}},
+		sendRequestErr: nil,
+		expectedResult: &AuthResponse{Status: http.StatusOK, Data: &identities},
+		resultErr:      nil,
+	},
+	{
+		name:           "error response",
+		authResponse:   &AuthResponse{Status: http.StatusUnauthorized, Data: &ErrorResponse{Status: 401, ErrorCode: "bad_jwt"}},
+		sendRequestErr: nil,
+		expectedResult: &AuthResponse{Status: http.StatusUnauthorized, Data: &ErrorResponse{Status: 401, ErrorCode: "bad_jwt"}},
+		resultErr:      nil,
+	},
+	{
+		name:           "failed get identities with send request error",
+		authResponse:   nil,
+		sendRequestErr: errors.New("send request error"),
+		expectedResult: nil,
+		resultErr:      errors.New("send request error"),
+	},
+}
+
+func TestAuth_GetUserIdentities(t *testing.T) {
+	for _, tt := range getUserIdentitiesTests {
+		client := new(clientMock)
+		sut := &Auth{
+			client: client,
+		}
+
+		client.On("createAndSendRequestWithToken", http.MethodGet, "user", "abc123", nil, &User{}).
+			Return(tt.authResponse, tt.sendRequestErr)
+
+		result, err := sut.GetUserIdentities("abc123")
+
+		if err != nil {
+			assert.Equal(t, err.Error(), tt.resultErr.Error())
+		} else {
+			assert.Equal(t, err, nil)
+		}
+
+		assert.Equal(t, result, tt.expectedResult)
+	}
+}
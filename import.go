@@ -0,0 +1,396 @@
+package supauth
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	ErrMissingIdentifier       = errors.New("user has neither an email nor a phone")
+	ErrUnsupportedPasswordHash = errors.New("unsupported password hash, expected bcrypt, argon2 or firebase scrypt")
+	ErrUnknownImportColumn     = errors.New("unknown import column")
+)
+
+const defaultRetryBackoff = time.Second
+
+var supportedPasswordHashPrefixes = []string{"$2a$", "$2b$", "$2y$", "$argon2i$", "$argon2id$", "$fbscrypt$"}
+
+type ImportUser struct {
+	Email        string         `json:"email"`
+	Phone        string         `json:"phone"`
+	PasswordHash string         `json:"password_hash"`
+	UserMetadata map[string]any `json:"user_metadata"`
+	AppMetadata  map[string]any `json:"app_metadata"`
+	// EmailConfirmed and PhoneConfirmed mark the user's email and phone as
+	// already verified, so GoTrue does not send a confirmation.
+	EmailConfirmed bool `json:"email_confirmed"`
+	PhoneConfirmed bool `json:"phone_confirmed"`
+	// BanDuration is a Go duration string such as "24h". Users with one are
+	// created banned, for accounts that were disabled in the old system.
+	BanDuration string `json:"ban_duration"`
+}
+
+type ImportOptions struct {
+	// Concurrency is the number of users created at once, defaulting to 1.
+	Concurrency int
+	// Checkpoint, when set, is read for rows completed by a previous run and
+	// appended to as rows succeed, so an interrupted import can be resumed.
+	// Rows are recorded by number, so the input must not be reordered or
+	// edited between runs. An *os.File opened with
+	// os.O_RDWR|os.O_CREATE|os.O_APPEND works.
+	Checkpoint io.ReadWriter
+	// MaxRetries is how often a row is retried after a 429 or 5xx response,
+	// defaulting to none. Transport errors are not retried, as the user may
+	// already have been created.
+	MaxRetries int
+	// RetryBackoff is the wait before the first retry, doubling for each one
+	// after, defaulting to a second. A Retry-After header takes precedence.
+	RetryBackoff time.Duration
+	// RequestsPerSecond caps how fast users are created across all workers.
+	// Zero means no limit.
+	RequestsPerSecond float64
+}
+
+type ImportResult struct {
+	Row     int
+	Email   string
+	Phone   string
+	UserID  string
+	Status  int
+	Skipped bool
+	Err     error
+}
+
+type Importer struct {
+	admin   AdminInterface
+	options ImportOptions
+	limiter *rateLimiter
+	sleep   func(time.Duration)
+}
+
+func NewImporter(admin AdminInterface, options ImportOptions) *Importer {
+	importer := &Importer{
+		admin:   admin,
+		options: options,
+		sleep:   time.Sleep,
+	}
+
+	if options.RequestsPerSecond > 0 {
+		importer.limiter = &rateLimiter{
+			interval: time.Duration(float64(time.Second) / options.RequestsPerSecond),
+			now:      time.Now,
+			sleep:    time.Sleep,
+		}
+	}
+
+	return importer
+}
+
+// ReadImportUsersCSV reads users from a CSV file whose header names
+// ImportUser's JSON keys. An unknown column is an error, so a misspelt or
+// renamed flag cannot quietly import every user with it unset.
+func ReadImportUsersCSV(r io.Reader) ([]ImportUser, error) {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	header := records[0]
+	columns := jsonFieldNames(reflect.TypeOf(ImportUser{}))
+
+	for _, name := range header {
+		if !columns[strings.TrimSpace(name)] {
+			return nil, fmt.Errorf("%w: %q", ErrUnknownImportColumn, name)
+		}
+	}
+
+	users := make([]ImportUser, 0, len(records)-1)
+
+	for n, record := range records[1:] {
+		user := ImportUser{}
+
+		for col, name := range header {
+			err = user.setField(strings.TrimSpace(name), strings.TrimSpace(record[col]))
+			if err != nil {
+				return nil, fmt.Errorf("row %d: %w", n+1, err)
+			}
+		}
+
+		users = append(users, user)
+	}
+
+	return users, nil
+}
+
+// ReadImportUsersJSON reads a JSON array of users. Unknown keys are an error,
+// as they are for ReadImportUsersCSV.
+func ReadImportUsersJSON(r io.Reader) ([]ImportUser, error) {
+	var users []ImportUser
+
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+
+	err := decoder.Decode(&users)
+	if err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+func (u *ImportUser) setField(name, value string) error {
+	var err error
+
+	switch name {
+	case "email":
+		u.Email = value
+	case "phone":
+		u.Phone = value
+	case "password_hash":
+		u.PasswordHash = value
+	case "user_metadata":
+		u.UserMetadata, err = decodeMetadataColumn(value)
+	case "app_metadata":
+		u.AppMetadata, err = decodeMetadataColumn(value)
+	case "email_confirmed":
+		u.EmailConfirmed, err = parseBoolColumn(value)
+	case "phone_confirmed":
+		u.PhoneConfirmed, err = parseBoolColumn(value)
+	case "ban_duration":
+		u.BanDuration = value
+	}
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+
+	return nil
+}
+
+func parseBoolColumn(value string) (bool, error) {
+	if value == "" {
+		return false, nil
+	}
+
+	return strconv.ParseBool(value)
+}
+
+func decodeMetadataColumn(value string) (map[string]any, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	metadata := map[string]any{}
+	err := json.Unmarshal([]byte(value), &metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	return metadata, nil
+}
+
+func (u ImportUser) attributes() (AdminUserAttributes, error) {
+	if u.Email == "" && u.Phone == "" {
+		return AdminUserAttributes{}, ErrMissingIdentifier
+	}
+
+	if u.PasswordHash != "" && !passwordHashSupported(u.PasswordHash) {
+		return AdminUserAttributes{}, ErrUnsupportedPasswordHash
+	}
+
+	return AdminUserAttributes{
+		Email:        u.Email,
+		Phone:        u.Phone,
+		PasswordHash: u.PasswordHash,
+		EmailConfirm: u.EmailConfirmed && u.Email != "",
+		PhoneConfirm: u.PhoneConfirmed && u.Phone != "",
+		UserMetadata: u.UserMetadata,
+		AppMetadata:  u.AppMetadata,
+		BanDuration:  u.BanDuration,
+	}, nil
+}
+
+func passwordHashSupported(hash string) bool {
+	for _, prefix := range supportedPasswordHashPrefixes {
+		if strings.HasPrefix(hash, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Import creates every user through the admin API and reports a result per
+// row. Rows are numbered from 1 in the order given. A failed row does not stop
+// the import; only checkpoint read and write failures are returned as errors.
+func (i *Importer) Import(users []ImportUser) ([]ImportResult, error) {
+	completed, err := readCheckpoint(i.options.Checkpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]ImportResult, len(users))
+	sem := make(chan struct{}, max(i.options.Concurrency, 1))
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var checkpointErr error
+
+	for idx, user := range users {
+		row := idx + 1
+
+		if completed[row] {
+			results[idx] = ImportResult{Row: row, Email: user.Email, Phone: user.Phone, Skipped: true}
+			continue
+		}
+
+		wg.Add(1)
+		sem <- struct{}{}
+
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			results[idx] = i.importUser(row, user)
+			if results[idx].Err != nil || i.options.Checkpoint == nil {
+				return
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			_, err := fmt.Fprintf(i.options.Checkpoint, "%d\n", row)
+			if err != nil && checkpointErr == nil {
+				checkpointErr = err
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	return results, checkpointErr
+}
+
+func (i *Importer) importUser(row int, user ImportUser) ImportResult {
+	result := ImportResult{Row: row, Email: user.Email, Phone: user.Phone}
+
+	attributes, err := user.attributes()
+	if err != nil {
+		result.Err = err
+		return result
+	}
+
+	response, err := i.createUser(attributes)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+
+	result.Status = response.Status
+
+	switch data := response.Data.(type) {
+	case *User:
+		result.UserID = data.ID
+	case *ErrorResponse:
+		result.Err = data
+	}
+
+	return result
+}
+
+func (i *Importer) createUser(attributes AdminUserAttributes) (*AuthResponse, error) {
+	for attempt := 0; ; attempt++ {
+		i.limiter.wait()
+
+		response, err := i.admin.CreateUser(attributes)
+		if err != nil || attempt >= i.options.MaxRetries || !retryableStatus(response.Status) {
+			return response, err
+		}
+
+		i.sleep(i.retryDelay(attempt, response))
+	}
+}
+
+func (i *Importer) retryDelay(attempt int, response *AuthResponse) time.Duration {
+	if response.RetryAfter > 0 {
+		return response.RetryAfter
+	}
+
+	backoff := i.options.RetryBackoff
+	if backoff <= 0 {
+		backoff = defaultRetryBackoff
+	}
+
+	return backoff << attempt
+}
+
+func retryableStatus(status int) bool {
+	return status == http.StatusTooManyRequests || status >= http.StatusInternalServerError
+}
+
+// rateLimiter spaces calls to wait at least interval apart. A nil limiter
+// does not wait.
+type rateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+	now      func() time.Time
+	sleep    func(time.Duration)
+}
+
+func (r *rateLimiter) wait() {
+	if r == nil {
+		return
+	}
+
+	r.mu.Lock()
+	now := r.now()
+	slot := r.next
+	if slot.Before(now) {
+		slot = now
+	}
+	r.next = slot.Add(r.interval)
+	r.mu.Unlock()
+
+	r.sleep(slot.Sub(now))
+}
+
+func readCheckpoint(r io.Reader) (map[int]bool, error) {
+	completed := map[int]bool{}
+	if r == nil {
+		return completed, nil
+	}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		row, err := strconv.Atoi(line)
+		if err != nil {
+			return nil, fmt.Errorf("invalid checkpoint line %q: %w", line, err)
+		}
+
+		completed[row] = true
+	}
+
+	return completed, scanner.Err()
+}
@@ -0,0 +1,322 @@
+package supauth
+
+import (
+	"bytes"
+	"errors"
+	"github.com/go-playground/assert/v2"
+	"github.com/stretchr/testify/mock"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+type failingCheckpoint struct {
+	readErr  error
+	writeErr error
+}
+
+func (f *failingCheckpoint) Read(p []byte) (int, error) {
+	if f.readErr != nil {
+		return 0, f.readErr
+	}
+	return 0, io.EOF
+}
+
+func (f *failingCheckpoint) Write(p []byte) (int, error) {
+	return 0, f.writeErr
+}
+
+var readImportUsersCSVTests = []struct {
+	name          string
+	csv           string
+	expectedUsers []ImportUser
+	expectedErr   error
+}{
+	{
+		name: "successfully reads users",
+		csv: "email,phone,password_hash,user_metadata,app_metadata,email_confirmed, phone_confirmed ,ban_duration\n" +
+			`test@example.com,+447700900001,$2a$10$abc,"{""name"":""Test""}","{""plan"":""pro""}",true,false,` + "\n" +
+			`,+447700900000,,,,,true,24h` + "\n",
+		expectedUsers: []ImportUser{
+			{
+				Email:          "test@example.com",
+				Phone:          "+447700900001",
+				PasswordHash:   "$2a$10$abc",
+				UserMetadata:   map[string]any{"name": "Test"},
+				AppMetadata:    map[string]any{"plan": "pro"},
+				EmailConfirmed: true,
+			},
+			{
+				Phone:          "+447700900000",
+				PhoneConfirmed: true,
+				BanDuration:    "24h",
+			},
+		},
+		expectedErr: nil,
+	},
+	{
+		name:          "empty file",
+		csv:           "",
+		expectedUsers: nil,
+		expectedErr:   nil,
+	},
+	{
+		name:          "mismatched column count",
+		csv:           "email,phone\ntest@example.com\n",
+		expectedUsers: nil,
+		expectedErr:   errors.New("record on line 2: wrong number of fields"),
+	},
+	{
+		name:          "renamed column",
+		csv:           "email,confirmed\ntest@example.com,true\n",
+		expectedUsers: nil,
+		expectedErr:   errors.New("unknown import column: \"confirmed\""),
+	},
+	{
+		name:          "misspelt column without rows",
+		csv:           "email,emial_confirmed\n",
+		expectedUsers: nil,
+		expectedErr:   errors.New("unknown import column: \"emial_confirmed\""),
+	},
+	{
+		name:          "invalid metadata",
+		csv:           "email,user_metadata\ntest@example.com,{\n",
+		expectedUsers: nil,
+		expectedErr:   errors.New("row 1: user_metadata: unexpected end of JSON input"),
+	},
+	{
+		name:          "invalid email confirmed flag",
+		csv:           "email,email_confirmed\ntest@example.com,maybe\n",
+		expectedUsers: nil,
+		expectedErr:   errors.New("row 1: email_confirmed: strconv.ParseBool: parsing \"maybe\": invalid syntax"),
+	},
+	{
+		name:          "invalid phone confirmed flag",
+		csv:           "phone,phone_confirmed\n+447700900000,1x\n",
+		expectedUsers: nil,
+		expectedErr:   errors.New("row 1: phone_confirmed: strconv.ParseBool: parsing \"1x\": invalid syntax"),
+	},
+}
+
+func TestReadImportUsersCSV(t *testing.T) {
+	for _, tt := range readImportUsersCSVTests {
+		users, err := ReadImportUsersCSV(strings.NewReader(tt.csv))
+
+		if err != nil {
+			assert.Equal(t, err.Error(), tt.expectedErr.Error())
+		} else {
+			assert.Equal(t, tt.expectedErr, nil)
+		}
+
+		assert.Equal(t, users, tt.expectedUsers)
+	}
+}
+
+var readImportUsersJSONTests = []struct {
+	name          string
+	json          string
+	expectedUsers []ImportUser
+	expectedErr   error
+}{
+	{
+		name: "successfully reads users",
+		json: `[{"email": "test@example.com", "password_hash": "$argon2id$v=19$abc", "user_metadata": {"name": "Test"}, "email_confirmed": true}]`,
+		expectedUsers: []ImportUser{
+			{
+				Email:          "test@example.com",
+				PasswordHash:   "$argon2id$v=19$abc",
+				UserMetadata:   map[string]any{"name": "Test"},
+				EmailConfirmed: true,
+			},
+		},
+		expectedErr: nil,
+	},
+	{
+		name:          "unknown key",
+		json:          `[{"email": "test@example.com", "confirmed": true}]`,
+		expectedUsers: nil,
+		expectedErr:   errors.New("json: unknown field \"confirmed\""),
+	},
+	{
+		name:          "invalid json",
+		json:          `!`,
+		expectedUsers: nil,
+		expectedErr:   errors.New("invalid character '!' looking for beginning of value"),
+	},
+}
+
+func TestReadImportUsersJSON(t *testing.T) {
+	for _, tt := range readImportUsersJSONTests {
+		users, err := ReadImportUsersJSON(strings.NewReader(tt.json))
+
+		if err != nil {
+			assert.Equal(t, err.Error(), tt.expectedErr.Error())
+		} else {
+			assert.Equal(t, tt.expectedErr, nil)
+		}
+
+		assert.Equal(t, users, tt.expectedUsers)
+	}
+}
+
+func TestImporter_Import(t *testing.T) {
+	admin := new(adminMock)
+	checkpoint := bytes.NewBufferString("1\n\n")
+
+	users := []ImportUser{
+		{Email: "done@example.com", PasswordHash: "$2a$10$abc"},
+		{Email: "new@example.com", Phone: "+447700900001", PasswordHash: "$2b$10$abc", EmailConfirmed: true},
+		{Phone: "+447700900000", PhoneConfirmed: true, BanDuration: "876000h"},
+		{PasswordHash: "$2a$10$abc"},
+		{Email: "md5@example.com", PasswordHash: "5f4dcc3b5aa765d61d8327deb882cf99"},
+		{Email: "exists@example.com"},
+		{Email: "down@example.com"},
+	}
+
+	admin.On("CreateUser", AdminUserAttributes{Email: "new@example.com", Phone: "+447700900001", PasswordHash: "$2b$10$abc", EmailConfirm: true}).
+		Return(&AuthResponse{Status: http.StatusOK, Data: &User{ID: "user-2"}}, nil)
+	admin.On("CreateUser", AdminUserAttributes{Phone: "+447700900000", PhoneConfirm: true, BanDuration: "876000h"}).
+		Return(&AuthResponse{Status: http.StatusOK, Data: &User{ID: "user-3"}}, nil)
+	admin.On("CreateUser", AdminUserAttributes{Email: "exists@example.com"}).
+		Return(&AuthResponse{Status: http.StatusUnprocessableEntity, Data: &ErrorResponse{Status: 422, ErrorCode: "email_exists"}}, nil)
+	admin.On("CreateUser", AdminUserAttributes{Email: "down@example.com"}).
+		Return((*AuthResponse)(nil), errors.New("send request error"))
+
+	sut := NewImporter(admin, ImportOptions{Concurrency: 3, Checkpoint: checkpoint})
+
+	results, err := sut.Import(users)
+
+	assert.Equal(t, err, nil)
+	assert.Equal(t, len(results), 7)
+	assert.Equal(t, results[0], ImportResult{Row: 1, Email: "done@example.com", Skipped: true})
+	assert.Equal(t, results[1], ImportResult{Row: 2, Email: "new@example.com", Phone: "+447700900001", UserID: "user-2", Status: http.StatusOK})
+	assert.Equal(t, results[2], ImportResult{Row: 3, Phone: "+447700900000", UserID: "user-3", Status: http.StatusOK})
+	assert.Equal(t, results[3].Err, ErrMissingIdentifier)
+	assert.Equal(t, results[4].Err, ErrUnsupportedPasswordHash)
+	assert.Equal(t, results[5].Status, http.StatusUnprocessableEntity)
+	assert.Equal(t, results[5].Err.Error(), "422 email_exists: ")
+	assert.Equal(t, results[6].Err.Error(), "send request error")
+
+	completed, _ := readCheckpoint(strings.NewReader(checkpoint.String()))
+	assert.Equal(t, completed, map[int]bool{2: true, 3: true})
+	admin.AssertNumberOfCalls(t, "CreateUser", 4)
+}
+
+func TestImporter_ImportWithoutCheckpoint(t *testing.T) {
+	admin := new(adminMock)
+	admin.On("CreateUser", AdminUserAttributes{Email: "new@example.com"}).
+		Return(&AuthResponse{Status: http.StatusOK, Data: &User{ID: "user-1"}}, nil)
+
+	sut := NewImporter(admin, ImportOptions{})
+
+	results, err := sut.Import([]ImportUser{{Email: "new@example.com"}})
+
+	assert.Equal(t, err, nil)
+	assert.Equal(t, results, []ImportResult{{Row: 1, Email: "new@example.com", UserID: "user-1", Status: http.StatusOK}})
+}
+
+var importCheckpointErrorTests = []struct {
+	name        string
+	checkpoint  *failingCheckpoint
+	expectedErr error
+}{
+	{
+		name:        "checkpoint read error",
+		checkpoint:  &failingCheckpoint{readErr: errors.New("read error")},
+		expectedErr: errors.New("read error"),
+	},
+	{
+		name:        "checkpoint write error",
+		checkpoint:  &failingCheckpoint{writeErr: errors.New("write error")},
+		expectedErr: errors.New("write error"),
+	},
+}
+
+func TestImporter_ImportCheckpointErrors(t *testing.T) {
+	for _, tt := range importCheckpointErrorTests {
+		admin := new(adminMock)
+		admin.On("CreateUser", mock.Anything).
+			Return(&AuthResponse{Status: http.StatusOK, Data: &User{ID: "user-1"}}, nil)
+
+		sut := NewImporter(admin, ImportOptions{Checkpoint: tt.checkpoint})
+
+		_, err := sut.Import([]ImportUser{{Email: "new@example.com"}})
+
+		assert.Equal(t, err.Error(), tt.expectedErr.Error())
+	}
+}
+
+func TestImporter_ImportInvalidCheckpoint(t *testing.T) {
+	sut := NewImporter(new(adminMock), ImportOptions{Checkpoint: bytes.NewBufferString("one\n")})
+
+	results, err := sut.Import([]ImportUser{{Email: "new@example.com"}})
+
+	assert.Equal(t, results, nil)
+	assert.Equal(t, err.Error(), "invalid checkpoint line \"one\": strconv.Atoi: parsing \"one\": invalid syntax")
+}
+
+func TestImporter_ImportRetries(t *testing.T) {
+	admin := new(adminMock)
+	rateLimited := &AuthResponse{
+		Status:     http.StatusTooManyRequests,
+		Data:       &ErrorResponse{Status: 429, ErrorCode: "over_request_rate_limit"},
+		RetryAfter: 3 * time.Second,
+	}
+	unavailable := &AuthResponse{Status: http.StatusServiceUnavailable, Data: &ErrorResponse{Status: 503}}
+
+	admin.On("CreateUser", AdminUserAttributes{Email: "new@example.com"}).Return(rateLimited, nil).Once()
+	admin.On("CreateUser", AdminUserAttributes{Email: "new@example.com"}).Return(unavailable, nil).Once()
+	admin.On("CreateUser", AdminUserAttributes{Email: "new@example.com"}).
+		Return(&AuthResponse{Status: http.StatusOK, Data: &User{ID: "user-1"}}, nil).Once()
+	admin.On("CreateUser", AdminUserAttributes{Email: "down@example.com"}).Return(unavailable, nil).Times(3)
+	admin.On("CreateUser", AdminUserAttributes{Email: "exists@example.com"}).
+		Return(&AuthResponse{Status: http.StatusUnprocessableEntity, Data: &ErrorResponse{Status: 422, ErrorCode: "email_exists"}}, nil).Once()
+
+	sut := NewImporter(admin, ImportOptions{MaxRetries: 2, RetryBackoff: 100 * time.Millisecond})
+
+	var sleeps []time.Duration
+	sut.sleep = func(d time.Duration) { sleeps = append(sleeps, d) }
+
+	results, err := sut.Import([]ImportUser{{Email: "new@example.com"}, {Email: "down@example.com"}, {Email: "exists@example.com"}})
+
+	assert.Equal(t, err, nil)
+	assert.Equal(t, results[0], ImportResult{Row: 1, Email: "new@example.com", UserID: "user-1", Status: http.StatusOK})
+	assert.Equal(t, results[1].Status, http.StatusServiceUnavailable)
+	assert.Equal(t, results[2].Status, http.StatusUnprocessableEntity)
+	assert.Equal(t, sleeps, []time.Duration{3 * time.Second, 200 * time.Millisecond, 100 * time.Millisecond, 200 * time.Millisecond})
+	admin.AssertExpectations(t)
+}
+
+func TestImporter_RetryDelayDefaultBackoff(t *testing.T) {
+	sut := NewImporter(new(adminMock), ImportOptions{})
+
+	assert.Equal(t, sut.retryDelay(0, &AuthResponse{}), time.Second)
+	assert.Equal(t, sut.retryDelay(2, &AuthResponse{}), 4*time.Second)
+}
+
+func TestNewImporterRateLimit(t *testing.T) {
+	assert.Equal(t, NewImporter(new(adminMock), ImportOptions{}).limiter, nil)
+	assert.Equal(t, NewImporter(new(adminMock), ImportOptions{RequestsPerSecond: 4}).limiter.interval, 250*time.Millisecond)
+}
+
+func TestRateLimiter_Wait(t *testing.T) {
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	var sleeps []time.Duration
+	sut := &rateLimiter{
+		interval: 100 * time.Millisecond,
+		now:      func() time.Time { return now },
+		sleep:    func(d time.Duration) { sleeps = append(sleeps, d) },
+	}
+
+	sut.wait()
+	sut.wait()
+	sut.wait()
+
+	now = now.Add(time.Second)
+	sut.wait()
+
+	assert.Equal(t, sleeps, []time.Duration{0, 100 * time.Millisecond, 200 * time.Millisecond, 0})
+}
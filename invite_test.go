@@ -0,0 +1,167 @@
+package supauth
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/go-playground/assert/v2"
+	"net/http"
+	"testing"
+)
+
+const generatedLinkJSON = `{
+	"id": "abc123",
+	"email": "test@example.com",
+	"invited_at": "2024-05-01T10:00:00Z",
+	"action_link": "https://project.supabase.co/auth/v1/verify?token=h4sh&type=invite&redirect_to=https://app.example.com",
+	"email_otp": "123456",
+	"hashed_token": "h4sh",
+	"verification_type": "invite",
+	"redirect_to": "https://app.example.com",
+	"is_sso_user": false,
+	"tenant": "acme"
+}`
+
+var adminLinkTests = []struct {
+	name                 string
+	call                 func(sut *Admin) (*AuthResponse, error)
+	expectedEndpoint     string
+	expectedData         any
+	expectedSuccessValue any
+}{
+	{
+		name: "invite user",
+		call: func(sut *Admin) (*AuthResponse, error) {
+			return sut.InviteUserByEmail("test@example.com", AuthOptions{
+				RedirectTo: "https://app.example.com/welcome",
+				Data:       map[string]any{"team": "sales"},
+			})
+		},
+		expectedEndpoint:     "invite?redirect_to=https%3A%2F%2Fapp.example.com%2Fwelcome",
+		expectedData:         inviteRequest{Email: "test@example.com", Data: map[string]any{"team": "sales"}},
+		expectedSuccessValue: &User{},
+	},
+	{
+		name: "generate signup link",
+		call: func(sut *Admin) (*AuthResponse, error) {
+			return sut.GenerateLink(GenerateLinkParams{
+				Type:     GenerateLinkSignup,
+				Email:    "test@example.com",
+				Password: "password123",
+				Options:  AuthOptions{Data: map[string]any{"name": "Test"}},
+			})
+		},
+		expectedEndpoint: "admin/generate_link",
+		expectedData: generateLinkRequest{
+			GenerateLinkParams: GenerateLinkParams{
+				Type:     GenerateLinkSignup,
+				Email:    "test@example.com",
+				Password: "password123",
+				Options:  AuthOptions{Data: map[string]any{"name": "Test"}},
+			},
+			Data: map[string]any{"name": "Test"},
+		},
+		expectedSuccessValue: &GeneratedLink{},
+	},
+	{
+		name: "generate email change link",
+		call: func(sut *Admin) (*AuthResponse, error) {
+			return sut.GenerateLink(GenerateLinkParams{
+				Type:     GenerateLinkEmailChangeNew,
+				Email:    "test@example.com",
+				NewEmail: "new@example.com",
+				Options:  AuthOptions{RedirectTo: "https://app.example.com"},
+			})
+		},
+		expectedEndpoint: "admin/generate_link?redirect_to=https%3A%2F%2Fapp.example.com",
+		expectedData: generateLinkRequest{
+			GenerateLinkParams: GenerateLinkParams{
+				Type:     GenerateLinkEmailChangeNew,
+				Email:    "test@example.com",
+				NewEmail: "new@example.com",
+				Options:  AuthOptions{RedirectTo: "https://app.example.com"},
+			},
+		},
+		expectedSuccessValue: &GeneratedLink{},
+	},
+}
+
+func TestAdmin_Links(t *testing.T) {
+	for _, tt := range adminLinkTests {
+		client := new(clientMock)
+		sut := &Admin{
+			client:         client,
+			serviceRoleKey: "service123",
+		}
+		authResponse := &AuthResponse{Status: http.StatusOK, Data: tt.expectedSuccessValue}
+
+		client.On("createAndSendRequestWithToken", http.MethodPost, tt.expectedEndpoint, "service123", tt.expectedData, tt.expectedSuccessValue).
+			Return(authResponse, nil)
+
+		result, err := tt.call(sut)
+
+		assert.Equal(t, err, nil)
+		assert.Equal(t, result, authResponse)
+	}
+}
+
+func TestAdmin_GenerateLinkSendRequestError(t *testing.T) {
+	client := new(clientMock)
+	sut := &Admin{
+		client:         client,
+		serviceRoleKey: "service123",
+	}
+	params := GenerateLinkParams{Type: GenerateLinkMagicLink, Email: "test@example.com"}
+
+	client.On("createAndSendRequestWithToken", http.MethodPost, "admin/generate_link", "service123", generateLinkRequest{GenerateLinkParams: params}, &GeneratedLink{}).
+		Return((*AuthResponse)(nil), errors.New("send request error"))
+
+	result, err := sut.GenerateLink(params)
+
+	assert.Equal(t, result, (*AuthResponse)(nil))
+	assert.Equal(t, err.Error(), "send request error")
+}
+
+func TestGeneratedLink_JSON(t *testing.T) {
+	var link GeneratedLink
+	err := json.Unmarshal([]byte(generatedLinkJSON), &link)
+
+	assert.Equal(t, err, nil)
+	assert.Equal(t, link.ID, "abc123")
+	assert.Equal(t, link.Email, "test@example.com")
+	assert.Equal(t, link.InvitedAt.Valid, true)
+	assert.Equal(t, link.LinkProperties, LinkProperties{
+		ActionLink:       "https://project.supabase.co/auth/v1/verify?token=h4sh&type=invite&redirect_to=https://app.example.com",
+		EmailOTP:         "123456",
+		HashedToken:      "h4sh",
+		VerificationType: "invite",
+		RedirectTo:       "https://app.example.com",
+	})
+	assert.Equal(t, link.User.Extra, map[string]json.RawMessage{"tenant": json.RawMessage(`"acme"`)})
+
+	data, err := json.Marshal(link)
+
+	assert.Equal(t, err, nil)
+
+	var roundTrip GeneratedLink
+	_ = json.Unmarshal(data, &roundTrip)
+
+	assert.Equal(t, roundTrip, link)
+}
+
+func TestGeneratedLink_JSONWithoutExtra(t *testing.T) {
+	var link GeneratedLink
+	err := json.Unmarshal([]byte(`{"id":"abc123","action_link":"https://example.com"}`), &link)
+
+	assert.Equal(t, err, nil)
+	assert.Equal(t, link.User.Extra, nil)
+	assert.Equal(t, link.ActionLink, "https://example.com")
+}
+
+func TestGeneratedLink_InvalidJSON(t *testing.T) {
+	for _, data := range []string{`{"id": 1}`, `{"id": "abc123", "action_link": 1}`} {
+		var link GeneratedLink
+		err := json.Unmarshal([]byte(data), &link)
+
+		assert.NotEqual(t, err, nil)
+	}
+}
@@ -0,0 +1,47 @@
+package supauth
+
+import (
+	"encoding/json"
+	"maps"
+)
+
+// UserMetadataAs decodes the user's user_metadata into T, e.g.
+// supauth.UserMetadataAs[Profile](&session.User).
+func UserMetadataAs[T any](user *User) (T, error) {
+	return convertJSON[T](user.UserMetadata)
+}
+
+// AppMetadataAs decodes the user's app_metadata, including the provider
+// fields and any custom keys, into T.
+func AppMetadataAs[T any](user *User) (T, error) {
+	return convertJSON[T](user.AppMetadata)
+}
+
+// MergeMetadata returns a copy of current with the top-level keys of value
+// written over it. Sending the result as user metadata keeps keys that T does
+// not know about.
+func MergeMetadata[T any](current map[string]any, value T) (map[string]any, error) {
+	updates, err := convertJSON[map[string]any](value)
+	if err != nil {
+		return nil, err
+	}
+
+	merged := make(map[string]any, len(current)+len(updates))
+	maps.Copy(merged, current)
+	maps.Copy(merged, updates)
+
+	return merged, nil
+}
+
+func convertJSON[T any](value any) (T, error) {
+	var converted T
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return converted, err
+	}
+
+	err = json.Unmarshal(data, &converted)
+
+	return converted, err
+}
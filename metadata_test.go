@@ -0,0 +1,81 @@
+package supauth
+
+import (
+	"github.com/go-playground/assert/v2"
+	"testing"
+)
+
+type testProfile struct {
+	Name    string `json:"name"`
+	Theme   string `json:"theme,omitempty"`
+	Billing struct {
+		Plan string `json:"plan"`
+	} `json:"billing"`
+}
+
+type testClaims struct {
+	Provider string   `json:"provider"`
+	Roles    []string `json:"roles"`
+}
+
+func TestUserMetadataAs(t *testing.T) {
+	user := &User{
+		UserMetadata: map[string]interface{}{
+			"name":    "Test",
+			"billing": map[string]any{"plan": "pro"},
+			"other":   true,
+		},
+	}
+
+	profile, err := UserMetadataAs[testProfile](user)
+
+	assert.Equal(t, err, nil)
+	assert.Equal(t, profile.Name, "Test")
+	assert.Equal(t, profile.Billing.Plan, "pro")
+}
+
+func TestUserMetadataAsErrors(t *testing.T) {
+	_, err := UserMetadataAs[testProfile](&User{UserMetadata: map[string]interface{}{"ch": make(chan int)}})
+	assert.NotEqual(t, err, nil)
+
+	_, err = UserMetadataAs[testProfile](&User{UserMetadata: map[string]interface{}{"name": 1}})
+	assert.NotEqual(t, err, nil)
+}
+
+func TestAppMetadataAs(t *testing.T) {
+	user := &User{
+		AppMetadata: AppMetadata{
+			Provider: "email",
+			Extra:    map[string]any{"roles": []any{"admin"}},
+		},
+	}
+
+	claims, err := AppMetadataAs[testClaims](user)
+
+	assert.Equal(t, err, nil)
+	assert.Equal(t, claims, testClaims{Provider: "email", Roles: []string{"admin"}})
+}
+
+func TestMergeMetadata(t *testing.T) {
+	current := map[string]any{"name": "Old", "theme": "dark", "unknown": 1}
+	profile := testProfile{Name: "New"}
+	profile.Billing.Plan = "team"
+
+	merged, err := MergeMetadata(current, profile)
+
+	assert.Equal(t, err, nil)
+	assert.Equal(t, merged, map[string]any{
+		"name":    "New",
+		"theme":   "dark",
+		"unknown": 1,
+		"billing": map[string]any{"plan": "team"},
+	})
+	assert.Equal(t, current["name"], "Old")
+}
+
+func TestMergeMetadataError(t *testing.T) {
+	merged, err := MergeMetadata(nil, make(chan int))
+
+	assert.Equal(t, merged, nil)
+	assert.NotEqual(t, err, nil)
+}
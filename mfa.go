@@ -0,0 +1,177 @@
+package supauth
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+type FactorType string
+
+const (
+	FactorTypeTOTP     FactorType = "totp"
+	FactorTypeWebAuthn FactorType = "webauthn"
+	FactorTypePhone    FactorType = "phone"
+)
+
+// WebAuthnCeremony says whether a WebAuthn challenge registers a new
+// credential or authenticates with an existing one.
+type WebAuthnCeremony string
+
+const (
+	WebAuthnCeremonyCreate  WebAuthnCeremony = "create"
+	WebAuthnCeremonyRequest WebAuthnCeremony = "request"
+)
+
+var ErrNoWebAuthnPublicKey = errors.New("webauthn credential options have no publicKey")
+
+type EnrollFactorParams struct {
+	FactorType   FactorType `json:"factor_type"`
+	FriendlyName string     `json:"friendly_name,omitempty"`
+	// Issuer is shown in authenticator apps for TOTP factors.
+	Issuer string `json:"issuer,omitempty"`
+	// Phone is the number codes are sent to for phone factors.
+	Phone string `json:"phone,omitempty"`
+}
+
+type EnrolledFactor struct {
+	ID           string          `json:"id"`
+	Type         FactorType      `json:"type"`
+	FriendlyName string          `json:"friendly_name"`
+	TOTP         *TOTPEnrollment `json:"totp,omitempty"`
+	Phone        string          `json:"phone,omitempty"`
+}
+
+type TOTPEnrollment struct {
+	QRCode string `json:"qr_code"`
+	Secret string `json:"secret"`
+	URI    string `json:"uri"`
+}
+
+type ChallengeFactorParams struct {
+	// Channel sends a phone factor's code over "sms" (the default) or
+	// "whatsapp".
+	Channel string `json:"channel,omitempty"`
+	// WebAuthn is required for webauthn factors.
+	WebAuthn *WebAuthnParams `json:"webauthn,omitempty"`
+}
+
+type WebAuthnParams struct {
+	RPID      string   `json:"rp_id"`
+	RPOrigins []string `json:"rp_origins,omitempty"`
+}
+
+type FactorChallenge struct {
+	ID        string             `json:"id"`
+	Type      FactorType         `json:"type"`
+	ExpiresAt int64              `json:"expires_at"`
+	WebAuthn  *WebAuthnChallenge `json:"webauthn,omitempty"`
+}
+
+type WebAuthnChallenge struct {
+	Type WebAuthnCeremony `json:"type"`
+	// CredentialOptions are the PublicKeyCredentialCreationOptions or
+	// PublicKeyCredentialRequestOptions, wrapped in a "publicKey" object, with
+	// binary fields already base64url encoded.
+	CredentialOptions json.RawMessage `json:"credential_options"`
+}
+
+type VerifyFactorParams struct {
+	ChallengeID string `json:"challenge_id"`
+	// Code is the TOTP or phone code. Leave it empty for webauthn factors.
+	Code     string             `json:"code,omitempty"`
+	WebAuthn *WebAuthnAssertion `json:"webauthn,omitempty"`
+}
+
+type WebAuthnAssertion struct {
+	WebAuthnParams
+	// Type must match the challenge's WebAuthnChallenge.Type.
+	Type WebAuthnCeremony `json:"type"`
+	// CredentialResponse is the browser's PublicKeyCredential.toJSON().
+	CredentialResponse json.RawMessage `json:"credential_response"`
+}
+
+// PublicKey returns the options to pass to the browser's
+// PublicKeyCredential.parseCreationOptionsFromJSON or
+// parseRequestOptionsFromJSON, depending on Type.
+func (c *WebAuthnChallenge) PublicKey() (json.RawMessage, error) {
+	var options struct {
+		PublicKey json.RawMessage `json:"publicKey"`
+	}
+
+	err := json.Unmarshal(c.CredentialOptions, &options)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(options.PublicKey) == 0 {
+		return nil, ErrNoWebAuthnPublicKey
+	}
+
+	return options.PublicKey, nil
+}
+
+func (a *Auth) EnrollFactor(accessToken string, params EnrollFactorParams) (*AuthResponse, error) {
+	successResponse := &EnrolledFactor{}
+
+	return a.client.createAndSendRequestWithToken(http.MethodPost, "factors", accessToken, params, successResponse)
+}
+
+// ChallengeFactor starts verification of a factor. For webauthn factors that
+// have not been verified yet the challenge registers the credential;
+// otherwise it authenticates with it.
+func (a *Auth) ChallengeFactor(accessToken, factorID string, params ChallengeFactorParams) (*AuthResponse, error) {
+	successResponse := &FactorChallenge{}
+
+	return a.client.createAndSendRequestWithToken(http.MethodPost, factorEndpoint(factorID, "challenge"), accessToken, params, successResponse)
+}
+
+// VerifyFactor answers a challenge. On success the response holds a new
+// aal2 session.
+func (a *Auth) VerifyFactor(accessToken, factorID string, params VerifyFactorParams) (*AuthResponse, error) {
+	successResponse := &Authenticated{}
+
+	return a.client.createAndSendRequestWithToken(http.MethodPost, factorEndpoint(factorID, "verify"), accessToken, params, successResponse)
+}
+
+func (a *Auth) UnenrollFactor(accessToken, factorID string) (*AuthResponse, error) {
+	successResponse := &Factor{}
+
+	return a.client.createAndSendRequestWithToken(http.MethodDelete, factorEndpoint(factorID, ""), accessToken, nil, successResponse)
+}
+
+// ListUserFactors returns a user's MFA factors as *[]Factor.
+func (a *Admin) ListUserFactors(userID string) (*AuthResponse, error) {
+	successResponse := &[]Factor{}
+
+	return a.client.createAndSendRequestWithToken(http.MethodGet, adminFactorEndpoint(userID, ""), a.serviceRoleKey, nil, successResponse)
+}
+
+// DeleteUserFactor removes a user's MFA factor, for example when they lose
+// their device. GoTrue records the deletion in the project's audit log, so
+// there is nothing for callers to log themselves.
+func (a *Admin) DeleteUserFactor(userID, factorID string) (*AuthResponse, error) {
+	successResponse := &Factor{}
+
+	return a.client.createAndSendRequestWithToken(http.MethodDelete, adminFactorEndpoint(userID, factorID), a.serviceRoleKey, nil, successResponse)
+}
+
+func factorEndpoint(factorID, action string) string {
+	endpoint := fmt.Sprintf("factors/%s", url.PathEscape(factorID))
+	if action != "" {
+		endpoint += "/" + action
+	}
+
+	return endpoint
+}
+
+func adminFactorEndpoint(userID, factorID string) string {
+	endpoint := adminUserEndpoint(userID) + "/factors"
+	if factorID != "" {
+		endpoint += "/" + url.PathEscape(factorID)
+	}
+
+	return endpoint
+}
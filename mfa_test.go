@@ -0,0 +1,252 @@
+package supauth
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/go-playground/assert/v2"
+	"net/http"
+	"testing"
+)
+
+const webAuthnChallengeJSON = `{
+	"id": "challenge-1",
+	"type": "webauthn",
+	"expires_at": 1714557900,
+	"webauthn": {
+		"type": "create",
+		"credential_options": {
+			"publicKey": {
+				"challenge": "q8Jd3kYw",
+				"rp": {"name": "Example", "id": "app.example.com"},
+				"user": {"name": "test@example.com", "displayName": "Test", "id": "YWJjMTIz"},
+				"pubKeyCredParams": [{"type": "public-key", "alg": -7}],
+				"timeout": 300000
+			}
+		}
+	}
+}`
+
+var mfaTests = []struct {
+	name                 string
+	call                 func(sut *Auth) (*AuthResponse, error)
+	expectedMethod       string
+	expectedEndpoint     string
+	expectedData         any
+	expectedSuccessValue any
+}{
+	{
+		name: "enroll webauthn factor",
+		call: func(sut *Auth) (*AuthResponse, error) {
+			return sut.EnrollFactor("abc123", EnrollFactorParams{FactorType: FactorTypeWebAuthn, FriendlyName: "Laptop"})
+		},
+		expectedMethod:       http.MethodPost,
+		expectedEndpoint:     "factors",
+		expectedData:         EnrollFactorParams{FactorType: FactorTypeWebAuthn, FriendlyName: "Laptop"},
+		expectedSuccessValue: &EnrolledFactor{},
+	},
+	{
+		name: "challenge webauthn factor",
+		call: func(sut *Auth) (*AuthResponse, error) {
+			return sut.ChallengeFactor("abc123", "factor-1", ChallengeFactorParams{
+				WebAuthn: &WebAuthnParams{RPID: "app.example.com", RPOrigins: []string{"https://app.example.com"}},
+			})
+		},
+		expectedMethod:   http.MethodPost,
+		expectedEndpoint: "factors/factor-1/challenge",
+		expectedData: ChallengeFactorParams{
+			WebAuthn: &WebAuthnParams{RPID: "app.example.com", RPOrigins: []string{"https://app.example.com"}},
+		},
+		expectedSuccessValue: &FactorChallenge{},
+	},
+	{
+		name: "verify totp factor",
+		call: func(sut *Auth) (*AuthResponse, error) {
+			return sut.VerifyFactor("abc123", "factor/1", VerifyFactorParams{ChallengeID: "challenge-1", Code: "123456"})
+		},
+		expectedMethod:       http.MethodPost,
+		expectedEndpoint:     "factors/factor%2F1/verify",
+		expectedData:         VerifyFactorParams{ChallengeID: "challenge-1", Code: "123456"},
+		expectedSuccessValue: &Authenticated{},
+	},
+	{
+		name: "enroll phone factor",
+		call: func(sut *Auth) (*AuthResponse, error) {
+			return sut.EnrollFactor("abc123", EnrollFactorParams{FactorType: FactorTypePhone, Phone: "447700900000"})
+		},
+		expectedMethod:       http.MethodPost,
+		expectedEndpoint:     "factors",
+		expectedData:         EnrollFactorParams{FactorType: FactorTypePhone, Phone: "447700900000"},
+		expectedSuccessValue: &EnrolledFactor{},
+	},
+	{
+		name: "challenge phone factor over whatsapp",
+		call: func(sut *Auth) (*AuthResponse, error) {
+			return sut.ChallengeFactor("abc123", "factor-2", ChallengeFactorParams{Channel: "whatsapp"})
+		},
+		expectedMethod:       http.MethodPost,
+		expectedEndpoint:     "factors/factor-2/challenge",
+		expectedData:         ChallengeFactorParams{Channel: "whatsapp"},
+		expectedSuccessValue: &FactorChallenge{},
+	},
+	{
+		name:                 "unenroll factor",
+		call:                 func(sut *Auth) (*AuthResponse, error) { return sut.UnenrollFactor("abc123", "factor-1") },
+		expectedMethod:       http.MethodDelete,
+		expectedEndpoint:     "factors/factor-1",
+		expectedData:         nil,
+		expectedSuccessValue: &Factor{},
+	},
+}
+
+func TestAuth_MFA(t *testing.T) {
+	for _, tt := range mfaTests {
+		client := new(clientMock)
+		sut := &Auth{
+			client: client,
+		}
+		authResponse := &AuthResponse{Status: http.StatusOK, Data: tt.expectedSuccessValue}
+
+		client.On("createAndSendRequestWithToken", tt.expectedMethod, tt.expectedEndpoint, "abc123", tt.expectedData, tt.expectedSuccessValue).
+			Return(authResponse, nil)
+
+		result, err := tt.call(sut)
+
+		assert.Equal(t, err, nil)
+		assert.Equal(t, result, authResponse)
+	}
+}
+
+func TestAuth_VerifyFactorSendRequestError(t *testing.T) {
+	client := new(clientMock)
+	sut := &Auth{
+		client: client,
+	}
+	params := VerifyFactorParams{ChallengeID: "challenge-1", Code: "123456"}
+
+	client.On("createAndSendRequestWithToken", http.MethodPost, "factors/factor-1/verify", "abc123", params, &Authenticated{}).
+		Return((*AuthResponse)(nil), errors.New("send request error"))
+
+	result, err := sut.VerifyFactor("abc123", "factor-1", params)
+
+	assert.Equal(t, result, (*AuthResponse)(nil))
+	assert.Equal(t, err.Error(), "send request error")
+}
+
+var adminFactorTests = []struct {
+	name                 string
+	call                 func(sut *Admin) (*AuthResponse, error)
+	expectedMethod       string
+	expectedEndpoint     string
+	expectedSuccessValue any
+}{
+	{
+		name:                 "list user factors",
+		call:                 func(sut *Admin) (*AuthResponse, error) { return sut.ListUserFactors("user/1") },
+		expectedMethod:       http.MethodGet,
+		expectedEndpoint:     "admin/users/user%2F1/factors",
+		expectedSuccessValue: &[]Factor{},
+	},
+	{
+		name:                 "delete user factor",
+		call:                 func(sut *Admin) (*AuthResponse, error) { return sut.DeleteUserFactor("user-1", "factor/1") },
+		expectedMethod:       http.MethodDelete,
+		expectedEndpoint:     "admin/users/user-1/factors/factor%2F1",
+		expectedSuccessValue: &Factor{},
+	},
+}
+
+func TestAdmin_Factors(t *testing.T) {
+	for _, tt := range adminFactorTests {
+		client := new(clientMock)
+		sut := &Admin{
+			client:         client,
+			serviceRoleKey: "service123",
+		}
+		authResponse := &AuthResponse{Status: http.StatusOK, Data: tt.expectedSuccessValue}
+
+		client.On("createAndSendRequestWithToken", tt.expectedMethod, tt.expectedEndpoint, "service123", nil, tt.expectedSuccessValue).
+			Return(authResponse, nil)
+
+		result, err := tt.call(sut)
+
+		assert.Equal(t, err, nil)
+		assert.Equal(t, result, authResponse)
+	}
+}
+
+func TestWebAuthnChallenge_PublicKey(t *testing.T) {
+	var challenge FactorChallenge
+	err := json.Unmarshal([]byte(webAuthnChallengeJSON), &challenge)
+
+	assert.Equal(t, err, nil)
+	assert.Equal(t, challenge.Type, FactorTypeWebAuthn)
+	assert.Equal(t, challenge.WebAuthn.Type, WebAuthnCeremonyCreate)
+
+	publicKey, err := challenge.WebAuthn.PublicKey()
+
+	assert.Equal(t, err, nil)
+
+	var options map[string]any
+	_ = json.Unmarshal(publicKey, &options)
+
+	assert.Equal(t, options["challenge"], "q8Jd3kYw")
+	assert.Equal(t, options["rp"], map[string]any{"name": "Example", "id": "app.example.com"})
+}
+
+var webAuthnPublicKeyErrorTests = []struct {
+	name              string
+	credentialOptions string
+	expectedError     error
+}{
+	{
+		name:              "missing publicKey",
+		credentialOptions: `{"mediation": "conditional"}`,
+		expectedError:     ErrNoWebAuthnPublicKey,
+	},
+	{
+		name:              "invalid json",
+		credentialOptions: `[`,
+		expectedError:     errors.New("unexpected end of JSON input"),
+	},
+}
+
+func TestWebAuthnChallenge_PublicKeyError(t *testing.T) {
+	for _, tt := range webAuthnPublicKeyErrorTests {
+		challenge := &WebAuthnChallenge{CredentialOptions: json.RawMessage(tt.credentialOptions)}
+
+		publicKey, err := challenge.PublicKey()
+
+		assert.Equal(t, publicKey, nil)
+		assert.Equal(t, err.Error(), tt.expectedError.Error())
+	}
+}
+
+func TestEnrolledFactor_PhoneJSON(t *testing.T) {
+	var factor EnrolledFactor
+	err := json.Unmarshal([]byte(`{"id":"factor-2","type":"phone","friendly_name":"Mobile","phone":"447700900000"}`), &factor)
+
+	assert.Equal(t, err, nil)
+	assert.Equal(t, factor, EnrolledFactor{ID: "factor-2", Type: FactorTypePhone, FriendlyName: "Mobile", Phone: "447700900000"})
+
+	body, err := json.Marshal(ChallengeFactorParams{Channel: "sms"})
+
+	assert.Equal(t, err, nil)
+	assert.Equal(t, string(body), `{"channel":"sms"}`)
+}
+
+func TestVerifyFactorParams_WebAuthnJSON(t *testing.T) {
+	params := VerifyFactorParams{
+		ChallengeID: "challenge-1",
+		WebAuthn: &WebAuthnAssertion{
+			WebAuthnParams:     WebAuthnParams{RPID: "app.example.com"},
+			Type:               WebAuthnCeremonyRequest,
+			CredentialResponse: json.RawMessage(`{"id":"cred-1","type":"public-key"}`),
+		},
+	}
+
+	body, err := json.Marshal(params)
+
+	assert.Equal(t, err, nil)
+	assert.Equal(t, string(body), `{"challenge_id":"challenge-1","webauthn":{"rp_id":"app.example.com","type":"request",`+
+		`"credential_response":{"id":"cred-1","type":"public-key"}}}`)
+}
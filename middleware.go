@@ -0,0 +1,183 @@
+package supauth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	aal2                 = "aal2"
+	factorStatusVerified = "verified"
+	// authenticatedRole is the aud and role of a signed in user's access
+	// token. The anon and service role keys are signed with the same secret
+	// but carry their own role and no subject.
+	authenticatedRole = "authenticated"
+)
+
+type claimsContextKey struct{}
+
+// MFARequired is the 403 body RequireAAL2 sends when the session has to be
+// stepped up. Factors are the user's verified factors; the frontend
+// challenges one of them, or enrols one if there are none.
+type MFARequired struct {
+	ErrorResponse
+	CurrentLevel string   `json:"current_level"`
+	NextLevel    string   `json:"next_level"`
+	Factors      []Factor `json:"factors"`
+}
+
+// Authorizer enforces a PolicySet on net/http handlers using the access token
+// in the Authorization header.
+type Authorizer struct {
+	policies  *PolicySet
+	jwtSecret []byte
+	now       func() time.Time
+}
+
+func NewAuthorizer(policies *PolicySet, jwtSecret []byte) (*Authorizer, error) {
+	if len(jwtSecret) == 0 {
+		return nil, ErrMissingJWTSecret
+	}
+
+	return &Authorizer{
+		policies:  policies,
+		jwtSecret: jwtSecret,
+		now:       time.Now,
+	}, nil
+}
+
+// Require wraps next so it only runs for requests whose token satisfies
+// policy. Verified claims are available to next through ClaimsFromContext.
+// Requests without a valid token get a 401, those the policy denies get a 403
+// and those it cannot evaluate get a 500, all with an ErrorResponse body.
+// Require panics if policy is not in the set, so a typo fails at start-up
+// rather than on every request.
+func (a *Authorizer) Require(policy string, next http.Handler) http.Handler {
+	if _, ok := a.policies.Policies[policy]; !ok {
+		panic(fmt.Sprintf("supauth: %s: %q", ErrUnknownPolicy, policy))
+	}
+
+	return a.Authenticate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		claims, _ := ClaimsFromContext(r.Context())
+
+		allowed, err := a.policies.Allow(policy, claims)
+		if err != nil {
+			writeErrorResponse(w, http.StatusInternalServerError, "unexpected_failure", "Policy "+policy+" could not be evaluated")
+			return
+		}
+
+		if !allowed {
+			writeErrorResponse(w, http.StatusForbidden, "insufficient_permissions", "Access denied by policy "+policy)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	}))
+}
+
+// RequireAAL2 wraps next so it only runs once the user has completed MFA in
+// this session. Otherwise it responds with an MFARequired body, listing
+// factors fetched through auth. If they cannot be fetched the list is empty
+// and the frontend can list them itself.
+func (a *Authorizer) RequireAAL2(auth AuthInterface, next http.Handler) http.Handler {
+	return a.Authenticate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		claims, _ := ClaimsFromContext(r.Context())
+		if aalLevel(claims.AAL) >= aalLevel(aal2) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		token, _ := bearerToken(r)
+
+		body := MFARequired{
+			ErrorResponse: ErrorResponse{
+				Status:    http.StatusForbidden,
+				ErrorCode: "mfa_required",
+				Message:   "Complete multi-factor authentication to continue",
+			},
+			CurrentLevel: claims.AAL,
+			NextLevel:    aal2,
+			Factors:      verifiedFactors(auth, token),
+		}
+
+		writeJSON(w, http.StatusForbidden, body)
+	}))
+}
+
+// Authenticate wraps next so it only runs for requests with a valid user
+// access token, and adds the token's claims to the request context. API keys
+// are rejected even though they verify.
+func (a *Authorizer) Authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := bearerToken(r)
+		if !ok {
+			writeErrorResponse(w, http.StatusUnauthorized, "no_authorization", "Missing bearer token")
+			return
+		}
+
+		claims, err := VerifyToken(token, a.jwtSecret, a.now())
+		if err != nil {
+			writeErrorResponse(w, http.StatusUnauthorized, "bad_jwt", err.Error())
+			return
+		}
+
+		if claims.Audience != authenticatedRole || claims.Role != authenticatedRole || claims.Subject == "" {
+			writeErrorResponse(w, http.StatusUnauthorized, "bad_jwt", "Token is not a user access token")
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), claimsContextKey{}, claims)))
+	})
+}
+
+func ClaimsFromContext(ctx context.Context) (*Claims, bool) {
+	claims, ok := ctx.Value(claimsContextKey{}).(*Claims)
+
+	return claims, ok
+}
+
+func bearerToken(r *http.Request) (string, bool) {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") || token == "" {
+		return "", false
+	}
+
+	return token, true
+}
+
+func verifiedFactors(auth AuthInterface, accessToken string) []Factor {
+	factors := []Factor{}
+
+	authResponse, err := auth.GetUser(accessToken)
+	if err != nil {
+		return factors
+	}
+
+	user, ok := authResponse.Data.(*User)
+	if !ok {
+		return factors
+	}
+
+	for _, factor := range user.Factors {
+		if factor.Status == factorStatusVerified {
+			factors = append(factors, factor)
+		}
+	}
+
+	return factors
+}
+
+func writeErrorResponse(w http.ResponseWriter, status int, errorCode, message string) {
+	writeJSON(w, status, ErrorResponse{Status: status, ErrorCode: errorCode, Message: message})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	_ = json.NewEncoder(w).Encode(body)
+}
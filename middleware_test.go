@@ -0,0 +1,260 @@
+package supauth
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/go-playground/assert/v2"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+var okHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	claims, _ := ClaimsFromContext(r.Context())
+	_, _ = w.Write([]byte("hello " + claims.Subject))
+})
+
+func newTestAuthorizer() *Authorizer {
+	policies, _ := ParsePolicies([]byte(policiesYAML))
+
+	authorizer, _ := NewAuthorizer(policies, testJWTSecret)
+	authorizer.now = func() time.Time { return time.Unix(1714556400, 0) }
+
+	return authorizer
+}
+
+var requireTests = []struct {
+	name           string
+	policy         string
+	authorization  string
+	expectedStatus int
+	expectedBody   string
+}{
+	{
+		name:           "allowed",
+		policy:         "admin",
+		authorization:  "Bearer " + signedTestToken(`{"alg":"HS256"}`, accessTokenClaimsJSON, testJWTSecret),
+		expectedStatus: http.StatusOK,
+		expectedBody:   "hello abc123",
+	},
+	{
+		name:           "denied by policy",
+		policy:         "tier-two",
+		authorization:  "bearer " + signedTestToken(`{"alg":"HS256"}`, accessTokenClaimsJSON, testJWTSecret),
+		expectedStatus: http.StatusForbidden,
+		expectedBody:   `{"code":403,"error_code":"insufficient_permissions","msg":"Access denied by policy tier-two"}` + "\n",
+	},
+	{
+		name:           "missing token",
+		policy:         "admin",
+		authorization:  "",
+		expectedStatus: http.StatusUnauthorized,
+		expectedBody:   `{"code":401,"error_code":"no_authorization","msg":"Missing bearer token"}` + "\n",
+	},
+	{
+		name:           "basic auth",
+		policy:         "admin",
+		authorization:  "Basic dXNlcjpwYXNz",
+		expectedStatus: http.StatusUnauthorized,
+		expectedBody:   `{"code":401,"error_code":"no_authorization","msg":"Missing bearer token"}` + "\n",
+	},
+	{
+		name:           "anon key",
+		policy:         "members",
+		authorization:  "Bearer " + signedTestToken(`{"alg":"HS256"}`, anonKeyClaimsJSON, testJWTSecret),
+		expectedStatus: http.StatusUnauthorized,
+		expectedBody:   `{"code":401,"error_code":"bad_jwt","msg":"Token is not a user access token"}` + "\n",
+	},
+	{
+		name:   "service role key",
+		policy: "members",
+		authorization: "Bearer " + signedTestToken(`{"alg":"HS256"}`,
+			`{"iss": "supabase", "role": "service_role", "aud": "authenticated", "exp": 2029916400}`, testJWTSecret),
+		expectedStatus: http.StatusUnauthorized,
+		expectedBody:   `{"code":401,"error_code":"bad_jwt","msg":"Token is not a user access token"}` + "\n",
+	},
+	{
+		name:           "invalid token",
+		policy:         "admin",
+		authorization:  "Bearer " + signedTestToken(`{"alg":"HS256"}`, accessTokenClaimsJSON, []byte("other")),
+		expectedStatus: http.StatusUnauthorized,
+		expectedBody:   `{"code":401,"error_code":"bad_jwt","msg":"invalid access token signature"}` + "\n",
+	},
+}
+
+func TestAuthorizer_Require(t *testing.T) {
+	for _, tt := range requireTests {
+		sut := newTestAuthorizer()
+		handler := sut.Require(tt.policy, okHandler)
+
+		req := httptest.NewRequest(http.MethodGet, "/billing", nil)
+		if tt.authorization != "" {
+			req.Header.Set("Authorization", tt.authorization)
+		}
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		assert.Equal(t, rec.Code, tt.expectedStatus)
+		assert.Equal(t, rec.Body.String(), tt.expectedBody)
+	}
+}
+
+func TestAuthorizer_RequireUnevaluablePolicy(t *testing.T) {
+	sut := newTestAuthorizer()
+	sut.policies = &PolicySet{Policies: map[string][]Rule{"tier": {{Claim: "tenant_id", Equals: make(chan int)}}}}
+	handler := sut.Require("tier", okHandler)
+
+	req := httptest.NewRequest(http.MethodGet, "/billing", nil)
+	req.Header.Set("Authorization", "Bearer "+signedTestToken(`{"alg":"HS256"}`, accessTokenClaimsJSON, testJWTSecret))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	assert.Equal(t, rec.Code, http.StatusInternalServerError)
+	assert.Equal(t, rec.Body.String(), `{"code":500,"error_code":"unexpected_failure","msg":"Policy tier could not be evaluated"}`+"\n")
+}
+
+func TestAuthorizer_RequireUnknownPolicy(t *testing.T) {
+	sut := newTestAuthorizer()
+
+	defer func() {
+		assert.Equal(t, recover(), `supauth: unknown policy: "missing"`)
+	}()
+
+	sut.Require("missing", okHandler)
+}
+
+func TestNewAuthorizer(t *testing.T) {
+	sut, err := NewAuthorizer(&PolicySet{}, testJWTSecret)
+
+	assert.Equal(t, err, nil)
+	assert.Equal(t, sut.jwtSecret, testJWTSecret)
+	assert.NotEqual(t, sut.now, nil)
+}
+
+func TestNewAuthorizerEmptySecret(t *testing.T) {
+	for _, secret := range [][]byte{nil, {}} {
+		sut, err := NewAuthorizer(&PolicySet{}, secret)
+
+		assert.Equal(t, sut, nil)
+		assert.Equal(t, err, ErrMissingJWTSecret)
+	}
+}
+
+func TestClaimsFromContextMissing(t *testing.T) {
+	claims, ok := ClaimsFromContext(httptest.NewRequest(http.MethodGet, "/", nil).Context())
+
+	assert.Equal(t, claims, nil)
+	assert.Equal(t, ok, false)
+}
+
+const aal1ClaimsJSON = `{"sub": "abc123", "aud": "authenticated", "role": "authenticated", "exp": 1714560000, "aal": "aal1"}`
+
+// anonKeyClaimsJSON is shaped like a project's public anon key.
+const anonKeyClaimsJSON = `{"iss": "supabase", "ref": "project", "role": "anon", "iat": 1714556400, "exp": 2029916400}`
+
+var requireAAL2Tests = []struct {
+	name            string
+	claims          string
+	authResponse    *AuthResponse
+	sendRequestErr  error
+	expectedStatus  int
+	expectedFactors []Factor
+}{
+	{
+		name:           "aal2 session",
+		claims:         accessTokenClaimsJSON,
+		expectedStatus: http.StatusOK,
+	},
+	{
+		name:   "aal1 session with factors",
+		claims: aal1ClaimsJSON,
+		authResponse: &AuthResponse{
+			Status: http.StatusOK,
+			Data: &User{
+				ID: "abc123",
+				Factors: []Factor{
+					{ID: "factor-1", FactorType: FactorTypeTOTP, Status: "verified"},
+					{ID: "factor-2", FactorType: FactorTypeWebAuthn, Status: "unverified"},
+					{ID: "factor-3", FactorType: FactorTypePhone, Status: "verified", Phone: "447700900000"},
+				},
+			},
+		},
+		expectedStatus: http.StatusForbidden,
+		expectedFactors: []Factor{
+			{ID: "factor-1", FactorType: FactorTypeTOTP, Status: "verified"},
+			{ID: "factor-3", FactorType: FactorTypePhone, Status: "verified", Phone: "447700900000"},
+		},
+	},
+	{
+		name:   "aal1 session with api error",
+		claims: aal1ClaimsJSON,
+		authResponse: &AuthResponse{
+			Status: http.StatusUnauthorized,
+			Data:   &ErrorResponse{Status: http.StatusUnauthorized, ErrorCode: "bad_jwt", Message: "invalid JWT"},
+		},
+		expectedStatus:  http.StatusForbidden,
+		expectedFactors: []Factor{},
+	},
+	{
+		name:            "aal1 session with send request error",
+		claims:          aal1ClaimsJSON,
+		sendRequestErr:  errors.New("send request error"),
+		expectedStatus:  http.StatusForbidden,
+		expectedFactors: []Factor{},
+	},
+}
+
+func TestAuthorizer_RequireAAL2(t *testing.T) {
+	for _, tt := range requireAAL2Tests {
+		client := new(clientMock)
+		auth := &Auth{
+			client: client,
+		}
+		token := signedTestToken(`{"alg":"HS256"}`, tt.claims, testJWTSecret)
+
+		client.On("createAndSendRequestWithToken", http.MethodGet, "user", token, nil, &User{}).
+			Return(tt.authResponse, tt.sendRequestErr)
+
+		sut := newTestAuthorizer()
+		handler := sut.RequireAAL2(auth, okHandler)
+
+		req := httptest.NewRequest(http.MethodDelete, "/admin/users/abc123", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		assert.Equal(t, rec.Code, tt.expectedStatus)
+
+		if tt.expectedStatus == http.StatusOK {
+			assert.Equal(t, rec.Body.String(), "hello abc123")
+			continue
+		}
+
+		var body MFARequired
+		_ = json.Unmarshal(rec.Body.Bytes(), &body)
+
+		assert.Equal(t, rec.Header().Get("Content-Type"), "application/json")
+		assert.Equal(t, body.ErrorResponse, ErrorResponse{
+			Status:    http.StatusForbidden,
+			ErrorCode: "mfa_required",
+			Message:   "Complete multi-factor authentication to continue",
+		})
+		assert.Equal(t, body.CurrentLevel, "aal1")
+		assert.Equal(t, body.NextLevel, "aal2")
+		assert.Equal(t, body.Factors, tt.expectedFactors)
+	}
+}
+
+func TestAuthorizer_RequireAAL2WithoutToken(t *testing.T) {
+	sut := newTestAuthorizer()
+	handler := sut.RequireAAL2(&Auth{client: new(clientMock)}, okHandler)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/admin/users/abc123", nil))
+
+	assert.Equal(t, rec.Code, http.StatusUnauthorized)
+}
@@ -0,0 +1,213 @@
+package supauth
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// disabledUserBanDuration is the ban given to users that were disabled in
+// Firebase or blocked in Auth0. GoTrue has no permanent ban, so it is 100
+// years.
+const disabledUserBanDuration = "876000h"
+
+var ErrMissingFirebaseHashConfig = errors.New("firebase user has a password hash but no hash config was given")
+
+// FirebaseHashConfig holds the project's password hash parameters, found in
+// the Firebase console under Authentication > Users > Password hash parameters.
+type FirebaseHashConfig struct {
+	SignerKey     string
+	SaltSeparator string
+	Rounds        int
+	MemCost       int
+}
+
+type firebaseExport struct {
+	Users []firebaseUser `json:"users"`
+}
+
+type firebaseUser struct {
+	LocalID          string `json:"localId"`
+	Email            string `json:"email"`
+	EmailVerified    bool   `json:"emailVerified"`
+	PhoneNumber      string `json:"phoneNumber"`
+	PasswordHash     string `json:"passwordHash"`
+	Salt             string `json:"salt"`
+	DisplayName      string `json:"displayName"`
+	PhotoURL         string `json:"photoUrl"`
+	Disabled         bool   `json:"disabled"`
+	CustomAttributes string `json:"customAttributes"`
+	ProviderUserInfo []struct {
+		ProviderID string `json:"providerId"`
+	} `json:"providerUserInfo"`
+}
+
+type auth0User struct {
+	UserID        string         `json:"user_id"`
+	LegacyID      auth0ObjectID  `json:"_id"`
+	Email         string         `json:"email"`
+	EmailVerified bool           `json:"email_verified"`
+	PhoneNumber   string         `json:"phone_number"`
+	PhoneVerified bool           `json:"phone_verified"`
+	Blocked       bool           `json:"blocked"`
+	PasswordHash  string         `json:"passwordHash"`
+	Name          string         `json:"name"`
+	Picture       string         `json:"picture"`
+	UserMetadata  map[string]any `json:"user_metadata"`
+	AppMetadata   map[string]any `json:"app_metadata"`
+}
+
+type auth0ObjectID struct {
+	OID string `json:"$oid"`
+}
+
+// ReadFirebaseImportUsers converts the JSON written by `firebase auth:export`.
+// Password hashes are encoded in the $fbscrypt$ format GoTrue verifies on the
+// first sign in, and the Firebase UID is kept in app_metadata.firebase_uid.
+func ReadFirebaseImportUsers(r io.Reader, hashConfig FirebaseHashConfig) ([]ImportUser, error) {
+	export := firebaseExport{}
+
+	err := json.NewDecoder(r).Decode(&export)
+	if err != nil {
+		return nil, err
+	}
+
+	users := make([]ImportUser, 0, len(export.Users))
+
+	for _, fbUser := range export.Users {
+		user, err := fbUser.importUser(hashConfig)
+		if err != nil {
+			return nil, fmt.Errorf("firebase user %s: %w", fbUser.LocalID, err)
+		}
+
+		users = append(users, user)
+	}
+
+	return users, nil
+}
+
+func (f firebaseUser) importUser(hashConfig FirebaseHashConfig) (ImportUser, error) {
+	user := ImportUser{
+		Email:          f.Email,
+		Phone:          f.PhoneNumber,
+		EmailConfirmed: f.EmailVerified,
+		// Firebase only stores a phone number once it has been verified by SMS.
+		PhoneConfirmed: f.PhoneNumber != "",
+		UserMetadata:   map[string]any{},
+		AppMetadata:    map[string]any{"firebase_uid": f.LocalID},
+	}
+
+	if f.PasswordHash != "" {
+		if hashConfig.SignerKey == "" {
+			return ImportUser{}, ErrMissingFirebaseHashConfig
+		}
+
+		user.PasswordHash = fmt.Sprintf(
+			"$fbscrypt$v=1,n=%d,r=%d,p=1,ss=%s,sk=%s$%s$%s",
+			hashConfig.MemCost,
+			hashConfig.Rounds,
+			hashConfig.SaltSeparator,
+			hashConfig.SignerKey,
+			f.Salt,
+			f.PasswordHash,
+		)
+	}
+
+	if f.DisplayName != "" {
+		user.UserMetadata["full_name"] = f.DisplayName
+	}
+
+	if f.PhotoURL != "" {
+		user.UserMetadata["avatar_url"] = f.PhotoURL
+	}
+
+	if f.Disabled {
+		user.AppMetadata["firebase_disabled"] = true
+		user.BanDuration = disabledUserBanDuration
+	}
+
+	if len(f.ProviderUserInfo) > 0 {
+		providers := make([]string, 0, len(f.ProviderUserInfo))
+		for _, info := range f.ProviderUserInfo {
+			providers = append(providers, info.ProviderID)
+		}
+
+		user.AppMetadata["firebase_providers"] = providers
+	}
+
+	if f.CustomAttributes != "" {
+		claims := map[string]any{}
+
+		err := json.Unmarshal([]byte(f.CustomAttributes), &claims)
+		if err != nil {
+			return ImportUser{}, fmt.Errorf("customAttributes: %w", err)
+		}
+
+		user.AppMetadata["firebase_claims"] = claims
+	}
+
+	return user, nil
+}
+
+// ReadAuth0ImportUsers converts newline-delimited JSON from an Auth0 bulk user
+// export or a password hash export provided by Auth0 support. The Auth0 user
+// ID is kept in app_metadata.auth0_user_id.
+func ReadAuth0ImportUsers(r io.Reader) ([]ImportUser, error) {
+	decoder := json.NewDecoder(r)
+	users := []ImportUser{}
+
+	for line := 1; decoder.More(); line++ {
+		a0User := auth0User{}
+
+		err := decoder.Decode(&a0User)
+		if err != nil {
+			return nil, fmt.Errorf("auth0 user %d: %w", line, err)
+		}
+
+		users = append(users, a0User.importUser())
+	}
+
+	return users, nil
+}
+
+func (a auth0User) importUser() ImportUser {
+	userID := a.UserID
+	if userID == "" {
+		userID = "auth0|" + a.LegacyID.OID
+	}
+
+	user := ImportUser{
+		Email:          a.Email,
+		Phone:          a.PhoneNumber,
+		PasswordHash:   a.PasswordHash,
+		EmailConfirmed: a.EmailVerified,
+		PhoneConfirmed: a.PhoneVerified,
+		UserMetadata:   map[string]any{},
+		AppMetadata:    map[string]any{},
+	}
+
+	for key, value := range a.UserMetadata {
+		user.UserMetadata[key] = value
+	}
+
+	if a.Name != "" {
+		user.UserMetadata["full_name"] = a.Name
+	}
+
+	if a.Picture != "" {
+		user.UserMetadata["avatar_url"] = a.Picture
+	}
+
+	for key, value := range a.AppMetadata {
+		user.AppMetadata[key] = value
+	}
+
+	user.AppMetadata["auth0_user_id"] = userID
+
+	if a.Blocked {
+		user.BanDuration = disabledUserBanDuration
+	}
+
+	return user
+}
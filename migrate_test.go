@@ -0,0 +1,155 @@
+package supauth
+
+import (
+	"errors"
+	"github.com/go-playground/assert/v2"
+	"os"
+	"strings"
+	"testing"
+)
+
+var firebaseHashConfig = FirebaseHashConfig{
+	SignerKey:     "jxspr8Ki0RYycVU8zykbdLGjFQ3McFUH0uiiTvC8pVMXAn210wjLNmdZJzxUECKbm0QsEmYUSDzZvpjeJ9WmXA==",
+	SaltSeparator: "Bw==",
+	Rounds:        8,
+	MemCost:       14,
+}
+
+func TestReadFirebaseImportUsers(t *testing.T) {
+	file, err := os.Open("testdata/firebase_users.json")
+	assert.Equal(t, err, nil)
+	defer file.Close()
+
+	users, err := ReadFirebaseImportUsers(file, firebaseHashConfig)
+
+	assert.Equal(t, err, nil)
+	assert.Equal(t, users, []ImportUser{
+		{
+			Email: "ada@example.com",
+			PasswordHash: "$fbscrypt$v=1,n=14,r=8,p=1,ss=Bw==," +
+				"sk=jxspr8Ki0RYycVU8zykbdLGjFQ3McFUH0uiiTvC8pVMXAn210wjLNmdZJzxUECKbm0QsEmYUSDzZvpjeJ9WmXA==" +
+				"$42xEC+ixf3L2lw==" +
+				"$lSrfV15cpx95/sZS2W9c9Kp6i/LVgQNDNC/qzrCnh1SAyZvqmZqAjTdn3aoItz+VHjoZilo78198JAdRuid5lQ==",
+			EmailConfirmed: true,
+			UserMetadata: map[string]any{
+				"full_name":  "Ada Lovelace",
+				"avatar_url": "https://example.com/ada.png",
+			},
+			AppMetadata: map[string]any{
+				"firebase_uid":       "Zx3RfP1q8aU0b9LhA1yK2nW7c4T2",
+				"firebase_providers": []string{"password"},
+				"firebase_claims":    map[string]any{"admin": true},
+			},
+		},
+		{
+			Phone:          "+447700900123",
+			PhoneConfirmed: true,
+			UserMetadata:   map[string]any{},
+			AppMetadata: map[string]any{
+				"firebase_uid":       "9kLmQ2rS4tU6vW8xY0zA1bC3dE5f",
+				"firebase_providers": []string{"phone"},
+				"firebase_disabled":  true,
+			},
+			BanDuration: "876000h",
+		},
+		{
+			Email:        "grace@example.com",
+			UserMetadata: map[string]any{},
+			AppMetadata: map[string]any{
+				"firebase_uid":       "Gg7hJ8kK9lL0mM1nN2oO3pP4qQ5r",
+				"firebase_providers": []string{"google.com"},
+			},
+		},
+	})
+
+	for _, user := range users {
+		_, err = user.attributes()
+		assert.Equal(t, err, nil)
+	}
+}
+
+var readFirebaseImportUsersErrorTests = []struct {
+	name        string
+	json        string
+	hashConfig  FirebaseHashConfig
+	expectedErr error
+}{
+	{
+		name:        "invalid json",
+		json:        `!`,
+		hashConfig:  firebaseHashConfig,
+		expectedErr: errors.New("invalid character '!' looking for beginning of value"),
+	},
+	{
+		name:        "missing hash config",
+		json:        `{"users": [{"localId": "abc", "passwordHash": "aGFzaA==", "salt": "c2FsdA=="}]}`,
+		hashConfig:  FirebaseHashConfig{},
+		expectedErr: errors.New("firebase user abc: firebase user has a password hash but no hash config was given"),
+	},
+	{
+		name:        "invalid custom attributes",
+		json:        `{"users": [{"localId": "abc", "customAttributes": "{"}]}`,
+		hashConfig:  firebaseHashConfig,
+		expectedErr: errors.New("firebase user abc: customAttributes: unexpected end of JSON input"),
+	},
+}
+
+func TestReadFirebaseImportUsersErrors(t *testing.T) {
+	for _, tt := range readFirebaseImportUsersErrorTests {
+		users, err := ReadFirebaseImportUsers(strings.NewReader(tt.json), tt.hashConfig)
+
+		assert.Equal(t, users, nil)
+		assert.Equal(t, err.Error(), tt.expectedErr.Error())
+	}
+}
+
+func TestReadAuth0ImportUsers(t *testing.T) {
+	file, err := os.Open("testdata/auth0_users.ndjson")
+	assert.Equal(t, err, nil)
+	defer file.Close()
+
+	users, err := ReadAuth0ImportUsers(file)
+
+	assert.Equal(t, err, nil)
+	assert.Equal(t, users, []ImportUser{
+		{
+			Email:          "ada@example.com",
+			Phone:          "+447700900124",
+			EmailConfirmed: true,
+			UserMetadata: map[string]any{
+				"theme":      "dark",
+				"full_name":  "Ada Lovelace",
+				"avatar_url": "https://example.com/ada.png",
+			},
+			AppMetadata: map[string]any{
+				"roles":         []any{"admin"},
+				"auth0_user_id": "auth0|5f7c8ec7c33c6c004bbafe82",
+			},
+		},
+		{
+			Phone:          "+447700900123",
+			PhoneConfirmed: true,
+			UserMetadata:   map[string]any{},
+			AppMetadata:    map[string]any{"auth0_user_id": "sms|5f7c8ec7c33c6c004bbafe83"},
+		},
+		{
+			Email:        "grace@example.com",
+			PasswordHash: "$2b$10$C9HvD5IepkvGu8kx4BH9d.M1wbuY6NnJ4ctk3gF4Xm94oNgBDdn5a",
+			UserMetadata: map[string]any{},
+			AppMetadata:  map[string]any{"auth0_user_id": "auth0|5f7c8ec7c33c6c004bbafe84"},
+			BanDuration:  "876000h",
+		},
+	})
+
+	for _, user := range users {
+		_, err = user.attributes()
+		assert.Equal(t, err, nil)
+	}
+}
+
+func TestReadAuth0ImportUsersError(t *testing.T) {
+	users, err := ReadAuth0ImportUsers(strings.NewReader("{\"email\": \"a@example.com\"}\n{\"email\": 1}\n"))
+
+	assert.Equal(t, users, nil)
+	assert.Equal(t, err.Error(), "auth0 user 2: json: cannot unmarshal number into Go struct field auth0User.email of type string")
+}
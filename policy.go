@@ -0,0 +1,210 @@
+package supauth
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrEmptyPolicy        = errors.New("policy has no rules")
+	ErrEmptyRule          = errors.New("policy rule has no conditions")
+	ErrEqualsWithoutClaim = errors.New("policy rule has equals without claim")
+	ErrInvalidAAL         = errors.New("policy rule aal must be aal1, aal2 or aal3")
+	ErrUnknownPolicy      = errors.New("unknown policy")
+)
+
+var aalPattern = regexp.MustCompile(`^aal[1-3]$`)
+
+// PolicySet holds named policies, each a list of rules that must all match.
+//
+//	policies:
+//	  billing-admin:
+//	    - role: admin
+//	    - aal: aal2
+//	    - any:
+//	        - email_domain: example.com
+//	        - claim: tenant_id
+//	          equals: acme
+type PolicySet struct {
+	Policies map[string][]Rule `yaml:"policies"`
+}
+
+// Rule is one condition on a user's claims. Set more than one field to
+// require all of them, or use Any to require at least one sub-rule.
+type Rule struct {
+	// Role must be in the app_metadata roles list.
+	Role string `yaml:"role"`
+	// Claim names a top-level claim, or a custom claim such as one added by
+	// an access token hook, that must equal Equals.
+	Claim  string `yaml:"claim"`
+	Equals any    `yaml:"equals"`
+	// AAL is the minimum assurance level: "aal1", "aal2" or "aal3".
+	AAL string `yaml:"aal"`
+	// EmailDomain must match the part of the email after the @, ignoring
+	// case.
+	EmailDomain string `yaml:"email_domain"`
+	IsAnonymous *bool  `yaml:"is_anonymous"`
+	Any         []Rule `yaml:"any"`
+}
+
+// ParsePolicies reads a PolicySet from YAML or JSON. Anything that would
+// quietly weaken a policy is an error: unknown keys, policies without rules,
+// rules without conditions and unrecognised assurance levels.
+func ParsePolicies(data []byte) (*PolicySet, error) {
+	policies := &PolicySet{}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+
+	err := decoder.Decode(policies)
+	if err != nil {
+		return nil, err
+	}
+
+	for name, rules := range policies.Policies {
+		if len(rules) == 0 {
+			return nil, fmt.Errorf("policy %q: %w", name, ErrEmptyPolicy)
+		}
+
+		for _, rule := range rules {
+			err = rule.validate()
+			if err != nil {
+				return nil, fmt.Errorf("policy %q: %w", name, err)
+			}
+		}
+	}
+
+	return policies, nil
+}
+
+// Allow reports whether claims satisfy every rule of the named policy.
+func (p *PolicySet) Allow(policy string, claims *Claims) (bool, error) {
+	rules, ok := p.Policies[policy]
+	if !ok {
+		return false, fmt.Errorf("%w: %q", ErrUnknownPolicy, policy)
+	}
+
+	for _, rule := range rules {
+		matches, err := rule.Matches(claims)
+		if err != nil || !matches {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+// Matches reports whether claims satisfy the rule. It fails only if a claim
+// or Equals value cannot be encoded as JSON for comparison.
+func (r Rule) Matches(claims *Claims) (bool, error) {
+	if r.Role != "" && !claims.HasRole(r.Role) {
+		return false, nil
+	}
+
+	if r.Claim != "" {
+		equal, err := claimEquals(claims, r.Claim, r.Equals)
+		if err != nil || !equal {
+			return false, err
+		}
+	}
+
+	if r.AAL != "" && aalLevel(claims.AAL) < aalLevel(r.AAL) {
+		return false, nil
+	}
+
+	if r.EmailDomain != "" && !emailInDomain(claims.Email, r.EmailDomain) {
+		return false, nil
+	}
+
+	if r.IsAnonymous != nil && claims.IsAnonymous != *r.IsAnonymous {
+		return false, nil
+	}
+
+	if len(r.Any) == 0 {
+		return true, nil
+	}
+
+	for _, rule := range r.Any {
+		matches, err := rule.Matches(claims)
+		if err != nil || matches {
+			return matches, err
+		}
+	}
+
+	return false, nil
+}
+
+func (r Rule) validate() error {
+	if r.Role == "" && r.Claim == "" && r.AAL == "" && r.EmailDomain == "" && r.IsAnonymous == nil && len(r.Any) == 0 {
+		return ErrEmptyRule
+	}
+
+	if r.Equals != nil && r.Claim == "" {
+		return ErrEqualsWithoutClaim
+	}
+
+	if r.AAL != "" && !aalPattern.MatchString(r.AAL) {
+		return fmt.Errorf("%w, got %q", ErrInvalidAAL, r.AAL)
+	}
+
+	for _, rule := range r.Any {
+		err := rule.validate()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// claimEquals compares values in the form they take after a JSON round trip,
+// so the YAML int 2 equals the claim float64 2.
+func claimEquals(claims *Claims, name string, want any) (bool, error) {
+	all, err := convertJSON[map[string]any](claims)
+	if err != nil {
+		return false, err
+	}
+
+	for key, value := range claims.Extra {
+		all[key] = value
+	}
+
+	got, ok := all[name]
+	if !ok {
+		return false, nil
+	}
+
+	got, err = convertJSON[any](got)
+	if err != nil {
+		return false, err
+	}
+
+	want, err = convertJSON[any](want)
+	if err != nil {
+		return false, err
+	}
+
+	return reflect.DeepEqual(got, want), nil
+}
+
+// aalLevel turns "aal2" into 2. Anything else is level 0.
+func aalLevel(aal string) int {
+	level, err := strconv.Atoi(strings.TrimPrefix(aal, "aal"))
+	if err != nil || !strings.HasPrefix(aal, "aal") {
+		return 0
+	}
+
+	return level
+}
+
+func emailInDomain(email, domain string) bool {
+	_, emailDomain, ok := strings.Cut(email, "@")
+
+	return ok && strings.EqualFold(emailDomain, domain)
+}
@@ -0,0 +1,285 @@
+package supauth
+
+import (
+	"errors"
+	"github.com/go-playground/assert/v2"
+	"testing"
+)
+
+const policiesYAML = `
+policies:
+  admin:
+    - role: admin
+  billing-admin:
+    - role: admin
+    - aal: aal2
+    - any:
+        - email_domain: example.com
+        - claim: tenant_id
+          equals: acme
+  members:
+    - is_anonymous: false
+  tier-two:
+    - claim: tier
+      equals: 2
+`
+
+const policiesJSON = `{"policies": {"staff": [{"email_domain": "Example.com", "is_anonymous": false}]}}`
+
+var policyClaims = &Claims{
+	Email:       "test@example.com",
+	AAL:         "aal2",
+	AppMetadata: AppMetadata{Extra: map[string]any{"roles": []any{"admin"}}},
+	Extra:       map[string]any{"tenant_id": "acme", "tier": float64(2)},
+}
+
+var allowTests = []struct {
+	name     string
+	policy   string
+	claims   *Claims
+	expected bool
+}{
+	{
+		name:     "role matches",
+		policy:   "admin",
+		claims:   policyClaims,
+		expected: true,
+	},
+	{
+		name:     "role missing",
+		policy:   "admin",
+		claims:   &Claims{},
+		expected: false,
+	},
+	{
+		name:     "all rules match",
+		policy:   "billing-admin",
+		claims:   policyClaims,
+		expected: true,
+	},
+	{
+		name:   "any rule matches by claim",
+		policy: "billing-admin",
+		claims: &Claims{
+			Email:       "test@example.org",
+			AAL:         "aal2",
+			AppMetadata: AppMetadata{Extra: map[string]any{"roles": []any{"admin"}}},
+			Extra:       map[string]any{"tenant_id": "acme"},
+		},
+		expected: true,
+	},
+	{
+		name:   "no any rule matches",
+		policy: "billing-admin",
+		claims: &Claims{
+			Email:       "test@example.org",
+			AAL:         "aal2",
+			AppMetadata: AppMetadata{Extra: map[string]any{"roles": []any{"admin"}}},
+		},
+		expected: false,
+	},
+	{
+		name:   "aal too low",
+		policy: "billing-admin",
+		claims: &Claims{
+			Email:       "test@example.com",
+			AAL:         "aal1",
+			AppMetadata: AppMetadata{Extra: map[string]any{"roles": []any{"admin"}}},
+		},
+		expected: false,
+	},
+	{
+		name:     "not anonymous",
+		policy:   "members",
+		claims:   &Claims{IsAnonymous: false},
+		expected: true,
+	},
+	{
+		name:     "anonymous",
+		policy:   "members",
+		claims:   &Claims{IsAnonymous: true},
+		expected: false,
+	},
+	{
+		name:     "numeric claim",
+		policy:   "tier-two",
+		claims:   policyClaims,
+		expected: true,
+	},
+	{
+		name:     "numeric claim differs",
+		policy:   "tier-two",
+		claims:   &Claims{Extra: map[string]any{"tier": float64(1)}},
+		expected: false,
+	},
+}
+
+func TestPolicySet_Allow(t *testing.T) {
+	policies, err := ParsePolicies([]byte(policiesYAML))
+	assert.Equal(t, err, nil)
+
+	for _, tt := range allowTests {
+		allowed, err := policies.Allow(tt.policy, tt.claims)
+
+		assert.Equal(t, err, nil)
+		assert.Equal(t, allowed, tt.expected)
+	}
+}
+
+func TestPolicySet_AllowUnknownPolicy(t *testing.T) {
+	policies := &PolicySet{}
+
+	allowed, err := policies.Allow("missing", policyClaims)
+
+	assert.Equal(t, allowed, false)
+	assert.Equal(t, errors.Is(err, ErrUnknownPolicy), true)
+	assert.Equal(t, err.Error(), "unknown policy: \"missing\"")
+}
+
+func TestParsePoliciesJSON(t *testing.T) {
+	policies, err := ParsePolicies([]byte(policiesJSON))
+
+	assert.Equal(t, err, nil)
+
+	allowed, _ := policies.Allow("staff", &Claims{Email: "test@EXAMPLE.com"})
+	assert.Equal(t, allowed, true)
+
+	allowed, _ = policies.Allow("staff", &Claims{Email: "not-an-email"})
+	assert.Equal(t, allowed, false)
+}
+
+var parsePoliciesErrorTests = []struct {
+	name          string
+	data          string
+	expectedError error
+}{
+	{
+		name:          "empty rule",
+		data:          "policies:\n  admin:\n    - {}\n",
+		expectedError: errors.New("policy \"admin\": policy rule has no conditions"),
+	},
+	{
+		name:          "empty any rule",
+		data:          "policies:\n  admin:\n    - any:\n        - role: admin\n        - {}\n",
+		expectedError: errors.New("policy \"admin\": policy rule has no conditions"),
+	},
+	{
+		name:          "policy without rules",
+		data:          "policies:\n  admin: []\n",
+		expectedError: errors.New("policy \"admin\": policy has no rules"),
+	},
+	{
+		name:          "numeric aal",
+		data:          "policies:\n  admin:\n    - aal: 2\n",
+		expectedError: errors.New("policy \"admin\": policy rule aal must be aal1, aal2 or aal3, got \"2\""),
+	},
+	{
+		name:          "unknown aal",
+		data:          "policies:\n  admin:\n    - any:\n        - aal: aal9\n",
+		expectedError: errors.New("policy \"admin\": policy rule aal must be aal1, aal2 or aal3, got \"aal9\""),
+	},
+	{
+		name:          "misspelt key",
+		data:          "policies:\n  admin:\n    - role: admin\n      emial_domain: example.com\n",
+		expectedError: errors.New("yaml: unmarshal errors:\n  line 4: field emial_domain not found in type supauth.Rule"),
+	},
+	{
+		name:          "misspelt key in json",
+		data:          `{"policies": {"admin": [{"role": "admin", "emial_domain": "example.com"}]}}`,
+		expectedError: errors.New("yaml: unmarshal errors:\n  line 1: field emial_domain not found in type supauth.Rule"),
+	},
+	{
+		name:          "equals without claim",
+		data:          "policies:\n  admin:\n    - role: admin\n      equals: acme\n",
+		expectedError: errors.New("policy \"admin\": policy rule has equals without claim"),
+	},
+	{
+		name:          "empty document",
+		data:          "",
+		expectedError: errors.New("EOF"),
+	},
+	{
+		name:          "invalid yaml",
+		data:          "policies: [",
+		expectedError: errors.New("yaml: line 1: did not find expected node content"),
+	},
+}
+
+func TestParsePoliciesErrors(t *testing.T) {
+	for _, tt := range parsePoliciesErrorTests {
+		policies, err := ParsePolicies([]byte(tt.data))
+
+		assert.Equal(t, policies, nil)
+		assert.Equal(t, err.Error(), tt.expectedError.Error())
+	}
+}
+
+var aalLevelTests = []struct {
+	aal      string
+	expected int
+}{
+	{aal: "aal1", expected: 1},
+	{aal: "aal2", expected: 2},
+	{aal: "", expected: 0},
+	{aal: "2", expected: 0},
+	{aal: "aalx", expected: 0},
+}
+
+func TestAalLevel(t *testing.T) {
+	for _, tt := range aalLevelTests {
+		assert.Equal(t, aalLevel(tt.aal), tt.expected)
+	}
+}
+
+func TestRule_MatchesMissingClaim(t *testing.T) {
+	matches, err := Rule{Claim: "tenant_id", Equals: "acme"}.Matches(&Claims{})
+	assert.Equal(t, err, nil)
+	assert.Equal(t, matches, false)
+
+	matches, err = Rule{Claim: "email", Equals: "test@example.com"}.Matches(policyClaims)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, matches, true)
+}
+
+var matchesUnencodableTests = []struct {
+	name   string
+	rule   Rule
+	claims *Claims
+}{
+	{
+		name:   "unencodable equals",
+		rule:   Rule{Claim: "tier", Equals: make(chan int)},
+		claims: policyClaims,
+	},
+	{
+		name:   "unencodable app_metadata",
+		rule:   Rule{Claim: "tier", Equals: 2},
+		claims: &Claims{AppMetadata: AppMetadata{Extra: map[string]any{"channel": make(chan int)}}},
+	},
+	{
+		name:   "unencodable custom claim",
+		rule:   Rule{Claim: "tier", Equals: 2},
+		claims: &Claims{Extra: map[string]any{"tier": make(chan int)}},
+	},
+	{
+		name:   "unencodable any rule",
+		rule:   Rule{Any: []Rule{{Claim: "tier", Equals: make(chan int)}}},
+		claims: policyClaims,
+	},
+}
+
+func TestRule_MatchesUnencodable(t *testing.T) {
+	for _, tt := range matchesUnencodableTests {
+		matches, err := tt.rule.Matches(tt.claims)
+
+		assert.Equal(t, matches, false)
+		assert.NotEqual(t, err, nil)
+	}
+
+	policies := &PolicySet{Policies: map[string][]Rule{"tier": {matchesUnencodableTests[0].rule}}}
+
+	allowed, err := policies.Allow("tier", policyClaims)
+
+	assert.Equal(t, allowed, false)
+	assert.NotEqual(t, err, nil)
+}
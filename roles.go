@@ -0,0 +1,214 @@
+package supauth
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+)
+
+const (
+	rolesKey       = "roles"
+	permissionsKey = "permissions"
+)
+
+// maxMetadataAttempts bounds how often ModifyAppMetadata writes a change
+// that other writers keep overwriting.
+const maxMetadataAttempts = 3
+
+var ErrMetadataBusy = errors.New("app_metadata change kept being overwritten by other writers")
+
+// Roles returns the "roles" list stored in app_metadata.
+func (a AppMetadata) Roles() []string {
+	return stringList(a.Extra[rolesKey])
+}
+
+// Permissions returns the "permissions" list stored in app_metadata.
+func (a AppMetadata) Permissions() []string {
+	return stringList(a.Extra[permissionsKey])
+}
+
+// ModifyAppMetadata reads the user's app_metadata, lets modify change it, and
+// writes back only the keys that changed. GoTrue has no conditional update,
+// so another writer's PUT can replace a key this one just wrote; two
+// GrantRoles calls racing on "roles" would otherwise lose one role. To catch
+// that, the user is read again after each write and modify is reapplied
+// until its change is present, up to maxMetadataAttempts writes before
+// giving up with ErrMetadataBusy. modify may therefore run several times and
+// must be idempotent, as the role and permission helpers are. It is still
+// best-effort: a stale write that lands after this check can undo the
+// change, so callers that cannot lose updates must serialise writes to a
+// user themselves. On success the response holds the user as last read. API
+// errors are returned in the response as usual.
+func (a *Admin) ModifyAppMetadata(userID string, modify func(appMetadata map[string]any)) (*AuthResponse, error) {
+	user, authResponse, err := a.getUserByID(userID)
+	if user == nil {
+		return authResponse, err
+	}
+
+	for attempt := 0; ; attempt++ {
+		changes, err := appMetadataChanges(user, modify)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(changes) == 0 {
+			return authResponse, nil
+		}
+
+		if attempt == maxMetadataAttempts {
+			return nil, ErrMetadataBusy
+		}
+
+		authResponse, err = a.UpdateUserByID(userID, AdminUserAttributes{AppMetadata: changes})
+		if err != nil {
+			return nil, err
+		}
+
+		if _, ok := authResponse.Data.(*User); !ok {
+			return authResponse, nil
+		}
+
+		user, authResponse, err = a.getUserByID(userID)
+		if user == nil {
+			return authResponse, err
+		}
+	}
+}
+
+func (a *Admin) GrantRoles(userID string, roles ...string) (*AuthResponse, error) {
+	return a.ModifyAppMetadata(userID, addToList(rolesKey, roles))
+}
+
+func (a *Admin) RevokeRoles(userID string, roles ...string) (*AuthResponse, error) {
+	return a.ModifyAppMetadata(userID, removeFromList(rolesKey, roles))
+}
+
+func (a *Admin) GrantPermissions(userID string, permissions ...string) (*AuthResponse, error) {
+	return a.ModifyAppMetadata(userID, addToList(permissionsKey, permissions))
+}
+
+func (a *Admin) RevokePermissions(userID string, permissions ...string) (*AuthResponse, error) {
+	return a.ModifyAppMetadata(userID, removeFromList(permissionsKey, permissions))
+}
+
+// getUserByID returns the user, or nil with the response when GoTrue
+// returned an error instead.
+func (a *Admin) getUserByID(id string) (*User, *AuthResponse, error) {
+	authResponse, err := a.GetUserByID(id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	user, _ := authResponse.Data.(*User)
+
+	return user, authResponse, nil
+}
+
+// appMetadataChanges applies modify to a copy of the user's app_metadata and
+// returns the keys it changed.
+func appMetadataChanges(user *User, modify func(appMetadata map[string]any)) (map[string]any, error) {
+	original, err := convertJSON[map[string]any](user.AppMetadata)
+	if err != nil {
+		return nil, err
+	}
+
+	modified := cloneJSON(original).(map[string]any)
+	modify(modified)
+
+	return changedKeys(original, modified)
+}
+
+func addToList(key string, values []string) func(map[string]any) {
+	return func(appMetadata map[string]any) {
+		list := stringList(appMetadata[key])
+
+		for _, value := range values {
+			if !slices.Contains(list, value) {
+				list = append(list, value)
+			}
+		}
+
+		appMetadata[key] = list
+	}
+}
+
+func removeFromList(key string, values []string) func(map[string]any) {
+	return func(appMetadata map[string]any) {
+		if _, ok := appMetadata[key]; !ok {
+			return
+		}
+
+		list := slices.DeleteFunc(stringList(appMetadata[key]), func(value string) bool {
+			return slices.Contains(values, value)
+		})
+
+		appMetadata[key] = list
+	}
+}
+
+// changedKeys returns the keys of modified that differ from original, with
+// removed keys set to nil, which GoTrue treats as a delete. Values are
+// compared in the form they take after a JSON round trip, so []string{"a"}
+// equals []any{"a"}; a value that cannot be encoded is an error.
+func changedKeys(original, modified map[string]any) (map[string]any, error) {
+	changes := map[string]any{}
+
+	for key, value := range modified {
+		normalised, err := convertJSON[any](value)
+		if err != nil {
+			return nil, fmt.Errorf("app_metadata %q: %w", key, err)
+		}
+
+		if !reflect.DeepEqual(normalised, original[key]) {
+			changes[key] = value
+		}
+	}
+
+	for key := range original {
+		if _, ok := modified[key]; !ok {
+			changes[key] = nil
+		}
+	}
+
+	return changes, nil
+}
+
+// cloneJSON deep copies a value decoded from JSON.
+func cloneJSON(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		clone := make(map[string]any, len(v))
+		for key, item := range v {
+			clone[key] = cloneJSON(item)
+		}
+
+		return clone
+	case []any:
+		clone := make([]any, len(v))
+		for i, item := range v {
+			clone[i] = cloneJSON(item)
+		}
+
+		return clone
+	}
+
+	return value
+}
+
+func stringList(value any) []string {
+	list := []string{}
+
+	switch values := value.(type) {
+	case []string:
+		list = append(list, values...)
+	case []any:
+		for _, value := range values {
+			if s, ok := value.(string); ok {
+				list = append(list, s)
+			}
+		}
+	}
+
+	return list
+}
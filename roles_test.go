@@ -0,0 +1,266 @@
+package supauth
+
+import (
+	"errors"
+	"github.com/go-playground/assert/v2"
+	"net/http"
+	"testing"
+)
+
+func rolesUserResponse(extra map[string]any) *AuthResponse {
+	return &AuthResponse{
+		Status: http.StatusOK,
+		Data: &User{
+			ID:          "abc123",
+			AppMetadata: AppMetadata{Provider: "email", Extra: extra},
+		},
+	}
+}
+
+var userNotFoundResponse = &AuthResponse{
+	Status: http.StatusNotFound,
+	Data:   &ErrorResponse{Status: http.StatusNotFound, ErrorCode: "user_not_found", Message: "User not found"},
+}
+
+var modifyAppMetadataTests = []struct {
+	name           string
+	call           func(sut *Admin) (*AuthResponse, error)
+	getResponses   []*AuthResponse
+	getErr         error
+	updates        []map[string]any
+	updateResponse *AuthResponse
+	updateErr      error
+	expectedResult *AuthResponse
+	expectedError  error
+}{
+	{
+		name: "grant role",
+		call: func(sut *Admin) (*AuthResponse, error) { return sut.GrantRoles("abc123", "admin", "billing") },
+		getResponses: []*AuthResponse{
+			rolesUserResponse(map[string]any{"roles": []any{"admin"}}),
+			rolesUserResponse(map[string]any{"roles": []any{"admin", "billing"}}),
+		},
+		updates:        []map[string]any{{"roles": []string{"admin", "billing"}}},
+		expectedResult: rolesUserResponse(map[string]any{"roles": []any{"admin", "billing"}}),
+		expectedError:  nil,
+	},
+	{
+		name: "concurrent grant overwrites the first write",
+		call: func(sut *Admin) (*AuthResponse, error) { return sut.GrantPermissions("abc123", "invoices:write") },
+		getResponses: []*AuthResponse{
+			rolesUserResponse(nil),
+			rolesUserResponse(map[string]any{"permissions": []any{"invoices:read"}}),
+			rolesUserResponse(map[string]any{"permissions": []any{"invoices:read", "invoices:write"}}),
+		},
+		updates: []map[string]any{
+			{"permissions": []string{"invoices:write"}},
+			{"permissions": []string{"invoices:read", "invoices:write"}},
+		},
+		expectedResult: rolesUserResponse(map[string]any{"permissions": []any{"invoices:read", "invoices:write"}}),
+		expectedError:  nil,
+	},
+	{
+		name: "revoke permission",
+		call: func(sut *Admin) (*AuthResponse, error) { return sut.RevokePermissions("abc123", "invoices:write") },
+		getResponses: []*AuthResponse{
+			rolesUserResponse(map[string]any{"permissions": []any{"invoices:write"}}),
+			rolesUserResponse(map[string]any{"permissions": []any{}}),
+		},
+		updates:        []map[string]any{{"permissions": []string{}}},
+		expectedResult: rolesUserResponse(map[string]any{"permissions": []any{}}),
+		expectedError:  nil,
+	},
+	{
+		name: "remove a key",
+		call: func(sut *Admin) (*AuthResponse, error) {
+			return sut.ModifyAppMetadata("abc123", func(appMetadata map[string]any) { delete(appMetadata, "tier") })
+		},
+		getResponses: []*AuthResponse{
+			rolesUserResponse(map[string]any{"tier": "gold"}),
+			rolesUserResponse(nil),
+		},
+		updates:        []map[string]any{{"tier": nil}},
+		expectedResult: rolesUserResponse(nil),
+		expectedError:  nil,
+	},
+	{
+		name: "revoke role the user does not have",
+		call: func(sut *Admin) (*AuthResponse, error) { return sut.RevokeRoles("abc123", "admin") },
+		getResponses: []*AuthResponse{
+			rolesUserResponse(nil),
+		},
+		updates:        nil,
+		expectedResult: rolesUserResponse(nil),
+		expectedError:  nil,
+	},
+	{
+		name: "change keeps being overwritten",
+		call: func(sut *Admin) (*AuthResponse, error) { return sut.GrantRoles("abc123", "admin") },
+		getResponses: []*AuthResponse{
+			rolesUserResponse(nil),
+			rolesUserResponse(nil),
+			rolesUserResponse(nil),
+			rolesUserResponse(nil),
+		},
+		updates: []map[string]any{
+			{"roles": []string{"admin"}},
+			{"roles": []string{"admin"}},
+			{"roles": []string{"admin"}},
+		},
+		expectedResult: nil,
+		expectedError:  ErrMetadataBusy,
+	},
+	{
+		name: "user not found",
+		call: func(sut *Admin) (*AuthResponse, error) { return sut.GrantRoles("abc123", "admin") },
+		getResponses: []*AuthResponse{
+			userNotFoundResponse,
+		},
+		updates:        nil,
+		expectedResult: userNotFoundResponse,
+		expectedError:  nil,
+	},
+	{
+		name: "user deleted before the write",
+		call: func(sut *Admin) (*AuthResponse, error) { return sut.GrantRoles("abc123", "admin") },
+		getResponses: []*AuthResponse{
+			rolesUserResponse(nil),
+		},
+		updates:        []map[string]any{{"roles": []string{"admin"}}},
+		updateResponse: userNotFoundResponse,
+		expectedResult: userNotFoundResponse,
+		expectedError:  nil,
+	},
+	{
+		name: "user deleted after the write",
+		call: func(sut *Admin) (*AuthResponse, error) { return sut.GrantRoles("abc123", "admin") },
+		getResponses: []*AuthResponse{
+			rolesUserResponse(nil),
+			userNotFoundResponse,
+		},
+		updates:        []map[string]any{{"roles": []string{"admin"}}},
+		expectedResult: userNotFoundResponse,
+		expectedError:  nil,
+	},
+	{
+		name:           "send request error",
+		call:           func(sut *Admin) (*AuthResponse, error) { return sut.GrantRoles("abc123", "admin") },
+		getResponses:   nil,
+		getErr:         errors.New("send request error"),
+		updates:        nil,
+		expectedResult: nil,
+		expectedError:  errors.New("send request error"),
+	},
+	{
+		name: "update send request error",
+		call: func(sut *Admin) (*AuthResponse, error) { return sut.GrantRoles("abc123", "admin") },
+		getResponses: []*AuthResponse{
+			rolesUserResponse(nil),
+		},
+		updates:        []map[string]any{{"roles": []string{"admin"}}},
+		updateErr:      errors.New("send request error"),
+		expectedResult: nil,
+		expectedError:  errors.New("send request error"),
+	},
+}
+
+func TestAdmin_ModifyAppMetadata(t *testing.T) {
+	for _, tt := range modifyAppMetadataTests {
+		client := new(clientMock)
+		sut := &Admin{
+			client:         client,
+			serviceRoleKey: "service123",
+		}
+
+		for _, response := range tt.getResponses {
+			client.On("createAndSendRequestWithToken", http.MethodGet, "admin/users/abc123", "service123", nil, &User{}).
+				Return(response, nil).Once()
+		}
+
+		if tt.getErr != nil {
+			client.On("createAndSendRequestWithToken", http.MethodGet, "admin/users/abc123", "service123", nil, &User{}).
+				Return((*AuthResponse)(nil), tt.getErr).Once()
+		}
+
+		updateResponse := tt.updateResponse
+		if updateResponse == nil && tt.updateErr == nil {
+			updateResponse = &AuthResponse{Status: http.StatusOK, Data: &User{ID: "abc123"}}
+		}
+
+		for _, update := range tt.updates {
+			client.On("createAndSendRequestWithToken", http.MethodPut, "admin/users/abc123", "service123",
+				AdminUserAttributes{AppMetadata: update}, &User{}).
+				Return(updateResponse, tt.updateErr).Once()
+		}
+
+		result, err := tt.call(sut)
+
+		if tt.expectedError != nil {
+			assert.Equal(t, err.Error(), tt.expectedError.Error())
+		} else {
+			assert.Equal(t, err, nil)
+		}
+
+		assert.Equal(t, result, tt.expectedResult)
+		client.AssertExpectations(t)
+	}
+}
+
+var modifyAppMetadataUnencodableTests = []struct {
+	name        string
+	appMetadata map[string]any
+	modify      func(appMetadata map[string]any)
+}{
+	{
+		name:        "unencodable app_metadata",
+		appMetadata: map[string]any{"channel": make(chan int)},
+		modify:      func(appMetadata map[string]any) {},
+	},
+	{
+		name:        "unencodable change",
+		appMetadata: nil,
+		modify:      func(appMetadata map[string]any) { appMetadata["channel"] = make(chan int) },
+	},
+}
+
+func TestAdmin_ModifyAppMetadataUnencodable(t *testing.T) {
+	for _, tt := range modifyAppMetadataUnencodableTests {
+		client := new(clientMock)
+		sut := &Admin{
+			client:         client,
+			serviceRoleKey: "service123",
+		}
+
+		client.On("createAndSendRequestWithToken", http.MethodGet, "admin/users/abc123", "service123", nil, &User{}).
+			Return(rolesUserResponse(tt.appMetadata), nil).Once()
+
+		result, err := sut.ModifyAppMetadata("abc123", tt.modify)
+
+		assert.Equal(t, result, nil)
+		assert.NotEqual(t, err, nil)
+		client.AssertExpectations(t)
+	}
+}
+
+func TestCloneJSON(t *testing.T) {
+	original := map[string]any{"roles": []any{"admin"}, "limits": map[string]any{"seats": float64(5)}}
+
+	clone := cloneJSON(original).(map[string]any)
+	clone["roles"].([]any)[0] = "billing"
+	clone["limits"].(map[string]any)["seats"] = float64(10)
+
+	assert.Equal(t, original, map[string]any{"roles": []any{"admin"}, "limits": map[string]any{"seats": float64(5)}})
+}
+
+func TestAppMetadata_RolesAndPermissions(t *testing.T) {
+	appMetadata := AppMetadata{
+		Extra: map[string]any{
+			"roles":       []any{"admin", 1, "billing"},
+			"permissions": []string{"invoices:read"},
+		},
+	}
+
+	assert.Equal(t, appMetadata.Roles(), []string{"admin", "billing"})
+	assert.Equal(t, appMetadata.Permissions(), []string{"invoices:read"})
+	assert.Equal(t, AppMetadata{}.Roles(), []string{})
+}
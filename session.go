@@ -0,0 +1,100 @@
+package supauth
+
+import (
+	"cmp"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+var ErrNoSessionInURL = errors.New("redirect url has no session")
+
+var randRead = rand.Read
+
+type PKCE struct {
+	// Verifier is kept by the caller, e.g. in a cookie, until the redirect
+	// comes back and it is passed to ExchangeCodeForSession.
+	Verifier  string
+	Challenge string
+	Method    string
+}
+
+type pkceRequest struct {
+	AuthCode     string `json:"auth_code"`
+	CodeVerifier string `json:"code_verifier"`
+}
+
+func NewPKCE() (*PKCE, error) {
+	buf := make([]byte, 32)
+
+	_, err := randRead(buf)
+	if err != nil {
+		return nil, err
+	}
+
+	verifier := base64.RawURLEncoding.EncodeToString(buf)
+	challenge := sha256.Sum256([]byte(verifier))
+
+	return &PKCE{
+		Verifier:  verifier,
+		Challenge: base64.RawURLEncoding.EncodeToString(challenge[:]),
+		Method:    "s256",
+	}, nil
+}
+
+// ExchangeCodeForSession swaps the code from a PKCE redirect for a session.
+func (a *Auth) ExchangeCodeForSession(authCode, codeVerifier string) (*AuthResponse, error) {
+	reqBody := pkceRequest{
+		AuthCode:     authCode,
+		CodeVerifier: codeVerifier,
+	}
+
+	successResponse := &Authenticated{}
+
+	return a.client.createAndSendRequest(http.MethodPost, "token?grant_type=pkce", reqBody, successResponse)
+}
+
+// SessionFromRedirectURL reads the session GoTrue puts in the fragment of the
+// redirect URL at the end of an implicit SSO or OAuth flow. The fragment does
+// not include the user; fetch it with GetUser.
+func SessionFromRedirectURL(rawURL string) (*Authenticated, error) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	fragment, err := url.ParseQuery(parsed.EscapedFragment())
+	if err != nil {
+		return nil, err
+	}
+
+	for _, params := range []url.Values{fragment, parsed.Query()} {
+		if params.Get("error") != "" {
+			return nil, &ErrorResponse{
+				ErrorCode: cmp.Or(params.Get("error_code"), params.Get("error")),
+				Message:   params.Get("error_description"),
+			}
+		}
+	}
+
+	if fragment.Get("access_token") == "" {
+		return nil, ErrNoSessionInURL
+	}
+
+	expiresIn, _ := strconv.Atoi(fragment.Get("expires_in"))
+	expiresAt, _ := strconv.ParseInt(fragment.Get("expires_at"), 10, 64)
+
+	return &Authenticated{
+		AccessToken:          fragment.Get("access_token"),
+		TokenType:            fragment.Get("token_type"),
+		ExpiresIn:            expiresIn,
+		ExpiresAt:            expiresAt,
+		RefreshToken:         fragment.Get("refresh_token"),
+		ProviderToken:        fragment.Get("provider_token"),
+		ProviderRefreshToken: fragment.Get("provider_refresh_token"),
+	}, nil
+}
@@ -0,0 +1,148 @@
+package supauth
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"github.com/go-playground/assert/v2"
+	"net/http"
+	"testing"
+)
+
+func TestNewPKCE(t *testing.T) {
+	pkce, err := NewPKCE()
+
+	assert.Equal(t, err, nil)
+	assert.Equal(t, len(pkce.Verifier), 43)
+	assert.Equal(t, pkce.Method, "s256")
+
+	challenge := sha256.Sum256([]byte(pkce.Verifier))
+	assert.Equal(t, pkce.Challenge, base64.RawURLEncoding.EncodeToString(challenge[:]))
+}
+
+func TestNewPKCERandError(t *testing.T) {
+	originalRandRead := randRead
+	randRead = func(b []byte) (int, error) {
+		return 0, errors.New("rand error")
+	}
+	defer func() {
+		randRead = originalRandRead
+	}()
+
+	pkce, err := NewPKCE()
+
+	assert.Equal(t, pkce, nil)
+	assert.Equal(t, err.Error(), "rand error")
+}
+
+var exchangeCodeForSessionTests = []struct {
+	name           string
+	authResponse   *AuthResponse
+	sendRequestErr error
+	resultErr      error
+}{
+	{
+		name: "successful exchange",
+		authResponse: &AuthResponse{
+			Status: http.StatusOK,
+			Data:   &Authenticated{AccessToken: "cba321", User: User{ID: "abc123"}},
+		},
+		sendRequestErr: nil,
+		resultErr:      nil,
+	},
+	{
+		name:           "failed exchange with send request error",
+		authResponse:   nil,
+		sendRequestErr: errors.New("send request error"),
+		resultErr:      errors.New("send request error"),
+	},
+}
+
+func TestAuth_ExchangeCodeForSession(t *testing.T) {
+	for _, tt := range exchangeCodeForSessionTests {
+		client := new(clientMock)
+		sut := &Auth{
+			client: client,
+		}
+		reqBody := pkceRequest{AuthCode: "code123", CodeVerifier: "verifier123"}
+
+		client.On("createAndSendRequest", http.MethodPost, "token?grant_type=pkce", reqBody, &Authenticated{}).
+			Return(tt.authResponse, tt.sendRequestErr)
+
+		result, err := sut.ExchangeCodeForSession("code123", "verifier123")
+
+		if err != nil {
+			assert.Equal(t, err.Error(), tt.resultErr.Error())
+			assert.Equal(t, result, tt.authResponse)
+		} else {
+			assert.Equal(t, err, nil)
+			assert.Equal(t, result, tt.authResponse)
+		}
+	}
+}
+
+var sessionFromRedirectURLTests = []struct {
+	name          string
+	url           string
+	expectedAuth  *Authenticated
+	expectedError error
+}{
+	{
+		name: "session in fragment",
+		url: "https://app.example.com/callback#access_token=eyJ.a%2Bb&expires_at=1714557600&expires_in=3600" +
+			"&provider_token=pt&refresh_token=rt123&token_type=bearer&type=sso",
+		expectedAuth: &Authenticated{
+			AccessToken:   "eyJ.a+b",
+			TokenType:     "bearer",
+			ExpiresIn:     3600,
+			ExpiresAt:     1714557600,
+			RefreshToken:  "rt123",
+			ProviderToken: "pt",
+		},
+		expectedError: nil,
+	},
+	{
+		name:          "error in fragment",
+		url:           "https://app.example.com/callback#error=access_denied&error_code=saml_assertion_no_email&error_description=No+email",
+		expectedAuth:  nil,
+		expectedError: errors.New("saml_assertion_no_email: No email"),
+	},
+	{
+		name:          "error in query",
+		url:           "https://app.example.com/callback?error=server_error&error_description=Unable+to+exchange",
+		expectedAuth:  nil,
+		expectedError: errors.New("server_error: Unable to exchange"),
+	},
+	{
+		name:          "no session",
+		url:           "https://app.example.com/callback?code=abc",
+		expectedAuth:  nil,
+		expectedError: ErrNoSessionInURL,
+	},
+	{
+		name:          "invalid url",
+		url:           "://app.example.com",
+		expectedAuth:  nil,
+		expectedError: errors.New("parse \"://app.example.com\": missing protocol scheme"),
+	},
+	{
+		name:          "invalid fragment",
+		url:           "https://app.example.com/callback#access_token=a;refresh_token=b",
+		expectedAuth:  nil,
+		expectedError: errors.New("invalid semicolon separator in query"),
+	},
+}
+
+func TestSessionFromRedirectURL(t *testing.T) {
+	for _, tt := range sessionFromRedirectURLTests {
+		result, err := SessionFromRedirectURL(tt.url)
+
+		if tt.expectedError != nil {
+			assert.Equal(t, err.Error(), tt.expectedError.Error())
+		} else {
+			assert.Equal(t, err, nil)
+		}
+
+		assert.Equal(t, result, tt.expectedAuth)
+	}
+}
@@ -0,0 +1,175 @@
+package supauth
+
+import (
+	"errors"
+	"github.com/go-playground/assert/v2"
+	"net/http"
+	"testing"
+)
+
+var signInWithSSOTests = []struct {
+	name           string
+	params         SSOParams
+	expectedBody   ssoRequest
+	authResponse   *AuthResponse
+	sendRequestErr error
+	resultErr      error
+}{
+	{
+		name:   "successful sso by domain",
+		params: SSOParams{Domain: "example.com", Options: AuthOptions{RedirectTo: "https://app.example.com/callback"}},
+		expectedBody: ssoRequest{
+			SSOParams:        SSOParams{Domain: "example.com", Options: AuthOptions{RedirectTo: "https://app.example.com/callback"}},
+			RedirectTo:       "https://app.example.com/callback",
+			SkipHTTPRedirect: true,
+		},
+		authResponse: &AuthResponse{
+			Status: http.StatusOK,
+			Data:   &SSORedirect{URL: "https://idp.example.com/saml?SAMLRequest=abc"},
+		},
+		sendRequestErr: nil,
+		resultErr:      nil,
+	},
+	{
+		name: "successful sso by provider with pkce and captcha",
+		params: SSOParams{
+			ProviderID:          "provider-1",
+			CodeChallenge:       "challenge",
+			CodeChallengeMethod: "s256",
+			Options:             AuthOptions{CaptchaToken: "captcha123"},
+		},
+		expectedBody: ssoRequest{
+			SSOParams: SSOParams{
+				ProviderID:          "provider-1",
+				CodeChallenge:       "challenge",
+				CodeChallengeMethod: "s256",
+				Options:             AuthOptions{CaptchaToken: "captcha123"},
+			},
+			SkipHTTPRedirect: true,
+			Security:         &metaSecurity{CaptchaToken: "captcha123"},
+		},
+		authResponse: &AuthResponse{
+			Status: http.StatusOK,
+			Data:   &SSORedirect{URL: "https://idp.example.com/saml?SAMLRequest=abc"},
+		},
+		sendRequestErr: nil,
+		resultErr:      nil,
+	},
+	{
+		name:   "failed sso with send request error",
+		params: SSOParams{Domain: "example.com"},
+		expectedBody: ssoRequest{
+			SSOParams:        SSOParams{Domain: "example.com"},
+			SkipHTTPRedirect: true,
+		},
+		authResponse:   nil,
+		sendRequestErr: errors.New("send request error"),
+		resultErr:      errors.New("send request error"),
+	},
+}
+
+func TestAuth_SignInWithSSO(t *testing.T) {
+	for _, tt := range signInWithSSOTests {
+		client := new(clientMock)
+		sut := &Auth{
+			client: client,
+		}
+
+		client.On("createAndSendRequest", http.MethodPost, "sso", tt.expectedBody, &SSORedirect{}).
+			Return(tt.authResponse, tt.sendRequestErr)
+
+		result, err := sut.SignInWithSSO(tt.params)
+
+		if err != nil {
+			assert.Equal(t, err.Error(), tt.resultErr.Error())
+			assert.Equal(t, result, tt.authResponse)
+		} else {
+			assert.Equal(t, err, nil)
+			assert.Equal(t, result, tt.authResponse)
+		}
+	}
+}
+
+var samlAttributes = SSOProviderAttributes{
+	MetadataURL: "https://idp.example.com/metadata",
+	Domains:     []string{"example.com"},
+	AttributeMapping: &SAMLAttributeMapping{
+		Keys: map[string]SAMLAttribute{"email": {Name: "mail"}},
+	},
+}
+
+var adminSSOProviderTests = []struct {
+	name                 string
+	call                 func(sut *Admin) (*AuthResponse, error)
+	expectedMethod       string
+	expectedEndpoint     string
+	expectedData         any
+	expectedSuccessValue any
+}{
+	{
+		name:                 "list providers",
+		call:                 func(sut *Admin) (*AuthResponse, error) { return sut.ListSSOProviders() },
+		expectedMethod:       http.MethodGet,
+		expectedEndpoint:     "admin/sso/providers",
+		expectedData:         nil,
+		expectedSuccessValue: &SSOProviderList{},
+	},
+	{
+		name:             "create provider",
+		call:             func(sut *Admin) (*AuthResponse, error) { return sut.CreateSSOProvider(samlAttributes) },
+		expectedMethod:   http.MethodPost,
+		expectedEndpoint: "admin/sso/providers",
+		expectedData: SSOProviderAttributes{
+			Type:             "saml",
+			MetadataURL:      samlAttributes.MetadataURL,
+			Domains:          samlAttributes.Domains,
+			AttributeMapping: samlAttributes.AttributeMapping,
+		},
+		expectedSuccessValue: &SSOProvider{},
+	},
+	{
+		name:                 "get provider",
+		call:                 func(sut *Admin) (*AuthResponse, error) { return sut.GetSSOProvider("provider/1") },
+		expectedMethod:       http.MethodGet,
+		expectedEndpoint:     "admin/sso/providers/provider%2F1",
+		expectedData:         nil,
+		expectedSuccessValue: &SSOProvider{},
+	},
+	{
+		name: "update provider",
+		call: func(sut *Admin) (*AuthResponse, error) {
+			return sut.UpdateSSOProvider("provider-1", SSOProviderAttributes{Domains: []string{"example.org"}})
+		},
+		expectedMethod:       http.MethodPut,
+		expectedEndpoint:     "admin/sso/providers/provider-1",
+		expectedData:         SSOProviderAttributes{Domains: []string{"example.org"}},
+		expectedSuccessValue: &SSOProvider{},
+	},
+	{
+		name:                 "delete provider",
+		call:                 func(sut *Admin) (*AuthResponse, error) { return sut.DeleteSSOProvider("provider-1") },
+		expectedMethod:       http.MethodDelete,
+		expectedEndpoint:     "admin/sso/providers/provider-1",
+		expectedData:         nil,
+		expectedSuccessValue: &SSOProvider{},
+	},
+}
+
+func TestAdmin_SSOProviders(t *testing.T) {
+	for _, tt := range adminSSOProviderTests {
+		client := new(clientMock)
+		sut := &Admin{
+			client:         client,
+			serviceRoleKey: "service123",
+		}
+		authResponse := &AuthResponse{Status: http.StatusOK, Data: tt.expectedSuccessValue}
+
+		client.On("createAndSendRequestWithToken", tt.expectedMethod, tt.expectedEndpoint, "service123", tt.expectedData, tt.expectedSuccessValue).
+			Return(authResponse, nil)
+
+		result, err := tt.call(sut)
+
+		assert.Equal(t, err, nil)
+		assert.Equal(t, result, authResponse)
+	}
+}
@@ -0,0 +1,57 @@
+package supauth
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var ErrInvalidTimestamp = errors.New("invalid timestamp")
+
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999Z07",
+	"2006-01-02 15:04:05.999999999",
+}
+
+// NullTime is a timestamp GoTrue may leave unset, such as ConfirmedAt on a
+// user who has not confirmed yet. Valid is false when the field was null or
+// empty. Any other value that is not a timestamp is an error.
+type NullTime struct {
+	Time  time.Time
+	Valid bool
+}
+
+func (t NullTime) MarshalJSON() ([]byte, error) {
+	if !t.Valid {
+		return []byte("null"), nil
+	}
+
+	return t.Time.MarshalJSON()
+}
+
+func (t *NullTime) UnmarshalJSON(data []byte) error {
+	*t = NullTime{}
+
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidTimestamp, err)
+	}
+
+	if value == "" {
+		return nil
+	}
+
+	for _, layout := range timeLayouts {
+		parsed, err := time.Parse(layout, value)
+		if err == nil {
+			*t = NullTime{Time: parsed, Valid: true}
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: %q", ErrInvalidTimestamp, value)
+}
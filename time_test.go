@@ -0,0 +1,96 @@
+package supauth
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/go-playground/assert/v2"
+	"testing"
+	"time"
+)
+
+var nullTimeUnmarshalTests = []struct {
+	name          string
+	json          string
+	expected      NullTime
+	expectedError error
+}{
+	{
+		name:     "rfc3339 with fractional seconds",
+		json:     `"2024-05-01T10:00:00.123456Z"`,
+		expected: NullTime{Time: time.Date(2024, 5, 1, 10, 0, 0, 123456000, time.UTC), Valid: true},
+	},
+	{
+		name:     "rfc3339 with offset",
+		json:     `"2024-05-01T11:00:00+01:00"`,
+		expected: NullTime{Time: time.Date(2024, 5, 1, 11, 0, 0, 0, time.FixedZone("", 3600)), Valid: true},
+	},
+	{
+		name:     "no time zone",
+		json:     `"2024-05-01T10:00:00.123456"`,
+		expected: NullTime{Time: time.Date(2024, 5, 1, 10, 0, 0, 123456000, time.UTC), Valid: true},
+	},
+	{
+		name:     "postgres with short offset",
+		json:     `"2024-05-01 10:00:00.123456+00"`,
+		expected: NullTime{Time: time.Date(2024, 5, 1, 10, 0, 0, 123456000, time.FixedZone("", 0)), Valid: true},
+	},
+	{
+		name:     "null",
+		json:     `null`,
+		expected: NullTime{},
+	},
+	{
+		name:     "empty string",
+		json:     `""`,
+		expected: NullTime{},
+	},
+	{
+		name:          "unexpected format",
+		json:          `"yesterday"`,
+		expected:      NullTime{},
+		expectedError: errors.New("invalid timestamp: \"yesterday\""),
+	},
+	{
+		name:          "unexpected type",
+		json:          `1714557600`,
+		expected:      NullTime{},
+		expectedError: errors.New("invalid timestamp: json: cannot unmarshal number into Go value of type string"),
+	},
+}
+
+func TestNullTime_UnmarshalJSON(t *testing.T) {
+	for _, tt := range nullTimeUnmarshalTests {
+		value := NullTime{Time: time.Now(), Valid: true}
+
+		err := value.UnmarshalJSON([]byte(tt.json))
+
+		if tt.expectedError != nil {
+			assert.Equal(t, errors.Is(err, ErrInvalidTimestamp), true)
+			assert.Equal(t, err.Error(), tt.expectedError.Error())
+		} else {
+			assert.Equal(t, err, nil)
+		}
+		assert.Equal(t, value.Valid, tt.expected.Valid)
+		assert.Equal(t, value.Time.Equal(tt.expected.Time), true)
+	}
+}
+
+func TestNullTime_MarshalJSON(t *testing.T) {
+	data, err := json.Marshal(NullTime{})
+	assert.Equal(t, err, nil)
+	assert.Equal(t, string(data), "null")
+
+	data, err = json.Marshal(NullTime{Time: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC), Valid: true})
+	assert.Equal(t, err, nil)
+	assert.Equal(t, string(data), `"2024-05-01T10:00:00Z"`)
+}
+
+func TestSignUp_UnmarshalUnconfirmed(t *testing.T) {
+	signUp := SignUp{}
+
+	err := json.Unmarshal([]byte(`{"id": "abc123", "confirmed_at": null, "confirmation_sent_at": "2024-05-01T10:00:00Z"}`), &signUp)
+
+	assert.Equal(t, err, nil)
+	assert.Equal(t, signUp.ConfirmedAt.Valid, false)
+	assert.Equal(t, signUp.ConfirmationSentAt.Valid, true)
+}
@@ -0,0 +1,198 @@
+package supauth
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+)
+
+type User struct {
+	ID                 string                 `json:"id"`
+	Aud                string                 `json:"aud"`
+	Role               string                 `json:"role"`
+	Email              string                 `json:"email"`
+	Phone              string                 `json:"phone"`
+	NewEmail           string                 `json:"new_email"`
+	NewPhone           string                 `json:"new_phone"`
+	InvitedAt          NullTime               `json:"invited_at"`
+	ConfirmedAt        NullTime               `json:"confirmed_at"`
+	ConfirmationSentAt NullTime               `json:"confirmation_sent_at"`
+	EmailConfirmedAt   NullTime               `json:"email_confirmed_at"`
+	PhoneConfirmedAt   NullTime               `json:"phone_confirmed_at"`
+	EmailChangeSentAt  NullTime               `json:"email_change_sent_at"`
+	PhoneChangeSentAt  NullTime               `json:"phone_change_sent_at"`
+	LastSignInAt       NullTime               `json:"last_sign_in_at"`
+	AppMetadata        AppMetadata            `json:"app_metadata"`
+	UserMetadata       map[string]interface{} `json:"user_metadata"`
+	Identities         []Identity             `json:"identities"`
+	Factors            []Factor               `json:"factors"`
+	IsAnonymous        bool                   `json:"is_anonymous"`
+	IsSSOUser          bool                   `json:"is_sso_user"`
+	BannedUntil        NullTime               `json:"banned_until"`
+	DeletedAt          NullTime               `json:"deleted_at"`
+	CreatedAt          NullTime               `json:"created_at"`
+	UpdatedAt          NullTime               `json:"updated_at"`
+	// Extra holds fields GoTrue returned that User does not model yet. They
+	// are written back out when the user is encoded.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+type AppMetadata struct {
+	Provider  string   `json:"provider"`
+	Providers []string `json:"providers"`
+	// Extra holds every other key, such as custom roles set by the admin API.
+	Extra map[string]any `json:"-"`
+}
+
+type Identity struct {
+	IdentityID   string         `json:"identity_id"`
+	ID           string         `json:"id"`
+	UserID       string         `json:"user_id"`
+	IdentityData map[string]any `json:"identity_data"`
+	Provider     string         `json:"provider"`
+	Email        string         `json:"email"`
+	LastSignInAt NullTime       `json:"last_sign_in_at"`
+	CreatedAt    NullTime       `json:"created_at"`
+	UpdatedAt    NullTime       `json:"updated_at"`
+}
+
+type Factor struct {
+	ID           string     `json:"id"`
+	FriendlyName string     `json:"friendly_name"`
+	FactorType   FactorType `json:"factor_type"`
+	Status       string     `json:"status"`
+	Phone        string     `json:"phone,omitempty"`
+	CreatedAt    NullTime   `json:"created_at"`
+	UpdatedAt    NullTime   `json:"updated_at"`
+}
+
+// EmailChangePending reports whether the user has asked to change their email
+// and NewEmail is still waiting to be confirmed.
+func (u *User) EmailChangePending() bool {
+	return u.NewEmail != ""
+}
+
+// PhoneChangePending reports whether the user has asked to change their phone
+// and NewPhone is still waiting to be confirmed.
+func (u *User) PhoneChangePending() bool {
+	return u.NewPhone != ""
+}
+
+// IsBanned reports whether the user is banned at now.
+func (u *User) IsBanned(now time.Time) bool {
+	return u.BannedUntil.Valid && now.Before(u.BannedUntil.Time)
+}
+
+func (u *User) UnmarshalJSON(data []byte) error {
+	type user User
+	decoded := user{}
+
+	extra, err := unmarshalWithExtra[json.RawMessage](data, &decoded)
+	if err != nil {
+		return err
+	}
+
+	*u = User(decoded)
+	u.Extra = extra
+
+	return nil
+}
+
+func (u User) MarshalJSON() ([]byte, error) {
+	type user User
+
+	return marshalWithExtra(user(u), u.Extra)
+}
+
+func (a *AppMetadata) UnmarshalJSON(data []byte) error {
+	type appMetadata AppMetadata
+	decoded := appMetadata{}
+
+	extra, err := unmarshalWithExtra[any](data, &decoded)
+	if err != nil {
+		return err
+	}
+
+	*a = AppMetadata(decoded)
+	a.Extra = extra
+
+	return nil
+}
+
+func (a AppMetadata) MarshalJSON() ([]byte, error) {
+	type appMetadata AppMetadata
+
+	return marshalWithExtra(appMetadata(a), a.Extra)
+}
+
+// unmarshalWithExtra decodes data into the struct v points to and returns the
+// keys that do not belong to any of its fields.
+func unmarshalWithExtra[V any](data []byte, v any) (map[string]V, error) {
+	fields := map[string]V{}
+
+	err := json.Unmarshal(data, &fields)
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(data, v)
+	if err != nil {
+		return nil, err
+	}
+
+	for name := range jsonFieldNames(reflect.TypeOf(v).Elem()) {
+		delete(fields, name)
+	}
+
+	if len(fields) == 0 {
+		return nil, nil
+	}
+
+	return fields, nil
+}
+
+// marshalWithExtra encodes v and adds the extra keys that v does not set
+// itself.
+func marshalWithExtra[V any](v any, extra map[string]V) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil || len(extra) == 0 {
+		return data, err
+	}
+
+	fields := map[string]any{}
+	for name, value := range extra {
+		fields[name] = value
+	}
+
+	// data is a JSON object we have just encoded, so it always decodes.
+	known := map[string]json.RawMessage{}
+	_ = json.Unmarshal(data, &known)
+
+	for name, value := range known {
+		fields[name] = value
+	}
+
+	return json.Marshal(fields)
+}
+
+func jsonFieldNames(t reflect.Type) map[string]bool {
+	names := map[string]bool{}
+
+	for i := range t.NumField() {
+		field := t.Field(i)
+
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+
+		names[name] = true
+	}
+
+	return names
+}
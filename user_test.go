@@ -0,0 +1,244 @@
+package supauth
+
+import (
+	"encoding/json"
+	"github.com/go-playground/assert/v2"
+	"reflect"
+	"testing"
+	"time"
+)
+
+const userJSON = `{
+	"id": "abc123",
+	"aud": "authenticated",
+	"role": "authenticated",
+	"email": "test@example.com",
+	"phone": "447700900000",
+	"new_email": "new@example.com",
+	"email_change_sent_at": "2024-05-03T08:00:00Z",
+	"new_phone": "",
+	"email_confirmed_at": "2024-05-01T10:00:00.123456Z",
+	"phone_confirmed_at": null,
+	"confirmed_at": "2024-05-01T10:00:00.123456Z",
+	"last_sign_in_at": "2024-05-02T09:30:00Z",
+	"app_metadata": {
+		"provider": "email",
+		"providers": ["email", "google"],
+		"roles": ["admin", "billing"]
+	},
+	"user_metadata": {"name": "Test"},
+	"identities": [
+		{
+			"identity_id": "f8b1c2d3-0000-0000-0000-000000000001",
+			"id": "abc123",
+			"user_id": "abc123",
+			"identity_data": {"email": "test@example.com", "sub": "abc123"},
+			"provider": "email",
+			"email": "test@example.com",
+			"last_sign_in_at": "2024-05-02T09:30:00Z",
+			"created_at": "2024-05-01T10:00:00Z",
+			"updated_at": "2024-05-01T10:00:00Z"
+		}
+	],
+	"factors": [
+		{
+			"id": "factor-1",
+			"friendly_name": "Phone app",
+			"factor_type": "totp",
+			"status": "verified",
+			"created_at": "2024-05-01T10:00:00Z",
+			"updated_at": "2024-05-01T10:00:00Z"
+		}
+	],
+	"is_anonymous": false,
+	"is_sso_user": true,
+	"created_at": "2024-05-01T10:00:00Z",
+	"updated_at": "2024-05-02T09:30:00Z",
+	"new_field": {"nested": 1}
+}`
+
+func TestUser_UnmarshalJSON(t *testing.T) {
+	user := User{}
+
+	err := json.Unmarshal([]byte(userJSON), &user)
+
+	assert.Equal(t, err, nil)
+	assert.Equal(t, user.ID, "abc123")
+	assert.Equal(t, user.Phone, "447700900000")
+	assert.Equal(t, user.EmailConfirmedAt, NullTime{Time: time.Date(2024, 5, 1, 10, 0, 0, 123456000, time.UTC), Valid: true})
+	assert.Equal(t, user.PhoneConfirmedAt, NullTime{})
+	assert.Equal(t, user.LastSignInAt, NullTime{Time: time.Date(2024, 5, 2, 9, 30, 0, 0, time.UTC), Valid: true})
+	assert.Equal(t, user.BannedUntil, NullTime{})
+	assert.Equal(t, user.IsSSOUser, true)
+	assert.Equal(t, user.NewEmail, "new@example.com")
+	assert.Equal(t, user.EmailChangeSentAt, NullTime{Time: time.Date(2024, 5, 3, 8, 0, 0, 0, time.UTC), Valid: true})
+	assert.Equal(t, user.EmailChangePending(), true)
+	assert.Equal(t, user.PhoneChangePending(), false)
+	assert.Equal(t, user.AppMetadata.Provider, "email")
+	assert.Equal(t, user.AppMetadata.Providers, []string{"email", "google"})
+	assert.Equal(t, user.AppMetadata.Extra, map[string]any{"roles": []any{"admin", "billing"}})
+	assert.Equal(t, user.Identities[0].IdentityID, "f8b1c2d3-0000-0000-0000-000000000001")
+	assert.Equal(t, user.Identities[0].IdentityData["sub"], "abc123")
+	assert.Equal(t, user.Factors, []Factor{{
+		ID:           "factor-1",
+		FriendlyName: "Phone app",
+		FactorType:   "totp",
+		Status:       "verified",
+		CreatedAt:    NullTime{Time: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC), Valid: true},
+		UpdatedAt:    NullTime{Time: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC), Valid: true},
+	}})
+	assert.Equal(t, user.Extra, map[string]json.RawMessage{"new_field": json.RawMessage(`{"nested": 1}`)})
+}
+
+func TestUser_UnmarshalJSONWithoutExtra(t *testing.T) {
+	user := User{}
+
+	err := json.Unmarshal([]byte(`{"id": "abc123", "app_metadata": {"provider": "email"}}`), &user)
+
+	assert.Equal(t, err, nil)
+	assert.Equal(t, user.Extra, nil)
+	assert.Equal(t, user.AppMetadata, AppMetadata{Provider: "email"})
+}
+
+func TestUser_MarshalJSONPreservesExtra(t *testing.T) {
+	user := User{}
+	_ = json.Unmarshal([]byte(userJSON), &user)
+
+	data, err := json.Marshal(user)
+	assert.Equal(t, err, nil)
+
+	fields := map[string]any{}
+	_ = json.Unmarshal(data, &fields)
+
+	assert.Equal(t, fields["new_field"], map[string]any{"nested": float64(1)})
+	assert.Equal(t, fields["app_metadata"], map[string]any{
+		"provider":  "email",
+		"providers": []any{"email", "google"},
+		"roles":     []any{"admin", "billing"},
+	})
+	assert.Equal(t, fields["id"], "abc123")
+	assert.Equal(t, fields["email_confirmed_at"], "2024-05-01T10:00:00.123456Z")
+	assert.Equal(t, fields["phone_confirmed_at"], nil)
+}
+
+func TestUser_MarshalJSONKnownFieldsWin(t *testing.T) {
+	user := User{
+		ID:    "abc123",
+		Extra: map[string]json.RawMessage{"id": json.RawMessage(`"stale"`)},
+	}
+
+	data, err := json.Marshal(user)
+	assert.Equal(t, err, nil)
+
+	fields := map[string]any{}
+	_ = json.Unmarshal(data, &fields)
+
+	assert.Equal(t, fields["id"], "abc123")
+}
+
+var userJSONErrorTests = []struct {
+	name string
+	json string
+}{
+	{
+		name: "not an object",
+		json: `[]`,
+	},
+	{
+		name: "invalid field type",
+		json: `{"id": 1}`,
+	},
+	{
+		name: "invalid app metadata",
+		json: `{"app_metadata": []}`,
+	},
+	{
+		name: "invalid app metadata field type",
+		json: `{"app_metadata": {"provider": 1}}`,
+	},
+}
+
+func TestUser_UnmarshalJSONErrors(t *testing.T) {
+	for _, tt := range userJSONErrorTests {
+		user := User{}
+
+		err := json.Unmarshal([]byte(tt.json), &user)
+
+		assert.NotEqual(t, err, nil)
+	}
+}
+
+func TestJsonFieldNames(t *testing.T) {
+	type fields struct {
+		Tagged   string `json:"tagged,omitempty"`
+		Untagged string
+		Skipped  string `json:"-"`
+	}
+
+	names := jsonFieldNames(reflect.TypeOf(fields{}))
+
+	assert.Equal(t, names, map[string]bool{"tagged": true, "Untagged": true})
+}
+
+var userMarshalErrorTests = []struct {
+	name string
+	user User
+}{
+	{
+		name: "unsupported metadata value",
+		user: User{UserMetadata: map[string]interface{}{"ch": make(chan int)}},
+	},
+	{
+		name: "invalid extra value",
+		user: User{Extra: map[string]json.RawMessage{"broken": json.RawMessage(`{`)}},
+	},
+}
+
+func TestUser_MarshalJSONErrors(t *testing.T) {
+	for _, tt := range userMarshalErrorTests {
+		_, err := json.Marshal(tt.user)
+
+		assert.NotEqual(t, err, nil)
+	}
+}
+
+var isBannedTests = []struct {
+	name        string
+	bannedUntil NullTime
+	expected    bool
+}{
+	{
+		name:        "not banned",
+		bannedUntil: NullTime{},
+		expected:    false,
+	},
+	{
+		name:        "banned",
+		bannedUntil: NullTime{Time: time.Date(2024, 5, 2, 10, 0, 0, 0, time.UTC), Valid: true},
+		expected:    true,
+	},
+	{
+		name:        "ban expired",
+		bannedUntil: NullTime{Time: time.Date(2024, 4, 30, 10, 0, 0, 0, time.UTC), Valid: true},
+		expected:    false,
+	},
+}
+
+func TestUser_IsBanned(t *testing.T) {
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	for _, tt := range isBannedTests {
+		user := &User{BannedUntil: tt.bannedUntil}
+
+		assert.Equal(t, user.IsBanned(now), tt.expected)
+	}
+}
+
+func TestUser_BannedAndDeletedJSON(t *testing.T) {
+	var user User
+	err := json.Unmarshal([]byte(`{"id":"abc123","banned_until":"2024-05-02T10:00:00Z","deleted_at":"2024-05-01T09:00:00.5Z"}`), &user)
+
+	assert.Equal(t, err, nil)
+	assert.Equal(t, user.BannedUntil, NullTime{Time: time.Date(2024, 5, 2, 10, 0, 0, 0, time.UTC), Valid: true})
+	assert.Equal(t, user.DeletedAt, NullTime{Time: time.Date(2024, 5, 1, 9, 0, 0, 500000000, time.UTC), Valid: true})
+}
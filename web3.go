@@ -0,0 +1,284 @@
+package supauth
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+)
+
+type Web3Chain string
+
+const (
+	Web3ChainEthereum Web3Chain = "ethereum"
+	Web3ChainSolana   Web3Chain = "solana"
+)
+
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+const web3TimeLayout = "2006-01-02T15:04:05.000Z07:00"
+
+var (
+	ErrInvalidWeb3Message     = errors.New("invalid web3 sign in message")
+	ErrWeb3MessageExpired     = errors.New("web3 sign in message has expired")
+	ErrWeb3MessageNotYetValid = errors.New("web3 sign in message is not valid yet")
+	ErrInvalidSolanaAddress   = errors.New("invalid solana address")
+	ErrInvalidWeb3Signature   = errors.New("invalid web3 signature")
+)
+
+var web3HeaderPattern = regexp.MustCompile(`^(.+) wants you to sign in with your (Ethereum|Solana) account:$`)
+
+// Web3Message is a Sign-In with Ethereum (EIP-4361) or Sign-In with Solana
+// message. The wallet signs String() and the result is passed to
+// SignInWithWeb3 with the same text.
+type Web3Message struct {
+	Chain     Web3Chain
+	Domain    string
+	Address   string
+	Statement string
+	URI       string
+	// Version defaults to "1".
+	Version string
+	// ChainID is the EIP-155 chain ID for Ethereum, e.g. "1", or the cluster
+	// for Solana, e.g. "mainnet". Ethereum requires it.
+	ChainID        string
+	Nonce          string
+	IssuedAt       time.Time
+	ExpirationTime time.Time
+	NotBefore      time.Time
+	RequestID      string
+	Resources      []string
+}
+
+type Web3Credentials struct {
+	Chain   Web3Chain `json:"chain"`
+	Message string    `json:"message"`
+	// Signature is sent as the wallet produced it: hex for Ethereum and
+	// base64 for Solana.
+	Signature string      `json:"signature"`
+	Options   AuthOptions `json:"-"`
+}
+
+type web3Request struct {
+	Web3Credentials
+	Security *metaSecurity `json:"gotrue_meta_security,omitempty"`
+}
+
+// SignInWithWeb3 exchanges a signed Web3Message for a session. GoTrue checks
+// the signature, domain, URI and validity window itself.
+func (a *Auth) SignInWithWeb3(credentials Web3Credentials) (*AuthResponse, error) {
+	reqBody := web3Request{
+		Web3Credentials: credentials,
+		Security:        credentials.Options.security(),
+	}
+
+	successResponse := &Authenticated{}
+
+	return a.client.createAndSendRequest(http.MethodPost, "token?grant_type=web3", reqBody, successResponse)
+}
+
+func (m Web3Message) String() string {
+	var b strings.Builder
+
+	chainName := "Ethereum"
+	if m.Chain == Web3ChainSolana {
+		chainName = "Solana"
+	}
+
+	fmt.Fprintf(&b, "%s wants you to sign in with your %s account:\n%s\n", m.Domain, chainName, m.Address)
+
+	if m.Statement != "" {
+		fmt.Fprintf(&b, "\n%s\n", m.Statement)
+	} else if m.Chain != Web3ChainSolana {
+		b.WriteString("\n")
+	}
+
+	version := m.Version
+	if version == "" {
+		version = "1"
+	}
+
+	fmt.Fprintf(&b, "\nURI: %s\nVersion: %s", m.URI, version)
+
+	writeWeb3Field(&b, "Chain ID", m.ChainID)
+	writeWeb3Field(&b, "Nonce", m.Nonce)
+	writeWeb3Field(&b, "Issued At", formatWeb3Time(m.IssuedAt))
+	writeWeb3Field(&b, "Expiration Time", formatWeb3Time(m.ExpirationTime))
+	writeWeb3Field(&b, "Not Before", formatWeb3Time(m.NotBefore))
+	writeWeb3Field(&b, "Request ID", m.RequestID)
+
+	if len(m.Resources) > 0 {
+		b.WriteString("\nResources:")
+
+		for _, resource := range m.Resources {
+			fmt.Fprintf(&b, "\n- %s", resource)
+		}
+	}
+
+	return b.String()
+}
+
+// Validate checks the message's validity window against now.
+func (m Web3Message) Validate(now time.Time) error {
+	if !m.ExpirationTime.IsZero() && !now.Before(m.ExpirationTime) {
+		return ErrWeb3MessageExpired
+	}
+
+	if !m.NotBefore.IsZero() && now.Before(m.NotBefore) {
+		return ErrWeb3MessageNotYetValid
+	}
+
+	return nil
+}
+
+func ParseWeb3Message(message string) (*Web3Message, error) {
+	lines := strings.Split(message, "\n")
+	if len(lines) < 2 {
+		return nil, ErrInvalidWeb3Message
+	}
+
+	header := web3HeaderPattern.FindStringSubmatch(lines[0])
+	if header == nil {
+		return nil, fmt.Errorf("%w: unexpected header %q", ErrInvalidWeb3Message, lines[0])
+	}
+
+	m := &Web3Message{
+		Chain:   Web3ChainEthereum,
+		Domain:  header[1],
+		Address: lines[1],
+	}
+
+	if header[2] == "Solana" {
+		m.Chain = Web3ChainSolana
+	}
+
+	rest := lines[2:]
+	i := 0
+
+	for i < len(rest) && rest[i] == "" {
+		i++
+	}
+
+	if i < len(rest) && !strings.HasPrefix(rest[i], "URI: ") {
+		m.Statement = rest[i]
+		i++
+	}
+
+	for ; i < len(rest); i++ {
+		line := rest[i]
+		if line == "" {
+			continue
+		}
+
+		if line == "Resources:" {
+			for i+1 < len(rest) && strings.HasPrefix(rest[i+1], "- ") {
+				i++
+				m.Resources = append(m.Resources, strings.TrimPrefix(rest[i], "- "))
+			}
+
+			continue
+		}
+
+		err := m.setField(line)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if m.URI == "" || m.IssuedAt.IsZero() {
+		return nil, fmt.Errorf("%w: URI and Issued At are required", ErrInvalidWeb3Message)
+	}
+
+	return m, nil
+}
+
+func (m *Web3Message) setField(line string) error {
+	key, value, ok := strings.Cut(line, ": ")
+	if !ok {
+		return fmt.Errorf("%w: unexpected line %q", ErrInvalidWeb3Message, line)
+	}
+
+	var err error
+
+	switch key {
+	case "URI":
+		m.URI = value
+	case "Version":
+		m.Version = value
+	case "Chain ID":
+		m.ChainID = value
+	case "Nonce":
+		m.Nonce = value
+	case "Issued At":
+		m.IssuedAt, err = time.Parse(time.RFC3339Nano, value)
+	case "Expiration Time":
+		m.ExpirationTime, err = time.Parse(time.RFC3339Nano, value)
+	case "Not Before":
+		m.NotBefore, err = time.Parse(time.RFC3339Nano, value)
+	case "Request ID":
+		m.RequestID = value
+	default:
+		return fmt.Errorf("%w: unexpected field %q", ErrInvalidWeb3Message, key)
+	}
+
+	if err != nil {
+		return fmt.Errorf("%w: %s: %w", ErrInvalidWeb3Message, key, err)
+	}
+
+	return nil
+}
+
+// VerifySolanaSignature checks an ed25519 signature of message against a
+// base58 Solana address, without contacting GoTrue.
+func VerifySolanaSignature(message, address string, signature []byte) error {
+	publicKey, err := base58Decode(address)
+	if err != nil || len(publicKey) != ed25519.PublicKeySize {
+		return ErrInvalidSolanaAddress
+	}
+
+	if !ed25519.Verify(publicKey, []byte(message), signature) {
+		return ErrInvalidWeb3Signature
+	}
+
+	return nil
+}
+
+func writeWeb3Field(b *strings.Builder, name, value string) {
+	if value != "" {
+		fmt.Fprintf(b, "\n%s: %s", name, value)
+	}
+}
+
+func formatWeb3Time(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+
+	return t.UTC().Format(web3TimeLayout)
+}
+
+func base58Decode(s string) ([]byte, error) {
+	n := new(big.Int)
+	radix := big.NewInt(58)
+
+	for _, r := range s {
+		idx := strings.IndexRune(base58Alphabet, r)
+		if idx < 0 {
+			return nil, fmt.Errorf("invalid base58 character %q", r)
+		}
+
+		n.Mul(n, radix)
+		n.Add(n, big.NewInt(int64(idx)))
+	}
+
+	zeros := 0
+	for zeros < len(s) && s[zeros] == '1' {
+		zeros++
+	}
+
+	return append(make([]byte, zeros), n.Bytes()...), nil
+}
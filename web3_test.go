@@ -0,0 +1,345 @@
+package supauth
+
+import (
+	"encoding/hex"
+	"errors"
+	"github.com/go-playground/assert/v2"
+	"net/http"
+	"testing"
+	"time"
+)
+
+const solanaAddress = "9C6hybhQ6Aycep9jaUnP6uL9ZYvDjUp1aSkFWPUFJtpj"
+
+const solanaMessage = "app.example.com wants you to sign in with your Solana account:\n" +
+	solanaAddress + "\n\n" +
+	"Sign in to Example\n\n" +
+	"URI: https://app.example.com\n" +
+	"Version: 1\n" +
+	"Chain ID: mainnet\n" +
+	"Nonce: 32891756\n" +
+	"Issued At: 2024-05-01T10:00:00.000Z"
+
+// Signed with the ed25519 key from the seed 0x01..0x20.
+const solanaSignature = "7fcb748ab43c47104cdfbf35a904507e35b163206c46a2633cac72126ee5ae72" +
+	"d6500e3ea0bf9eae850724cafdae655dac0dd32b004a96d831ace37dce8d9108"
+
+const ethereumMessage = "service.invalid wants you to sign in with your Ethereum account:\n" +
+	"0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2\n\n" +
+	"I accept the ServiceOrg Terms of Service: https://service.invalid/tos\n\n" +
+	"URI: https://service.invalid/login\n" +
+	"Version: 1\n" +
+	"Chain ID: 1\n" +
+	"Nonce: 32891756\n" +
+	"Issued At: 2021-09-30T16:25:24.000Z\n" +
+	"Expiration Time: 2021-09-30T16:35:24.000Z\n" +
+	"Not Before: 2021-09-30T16:25:24.000Z\n" +
+	"Request ID: req-1\n" +
+	"Resources:\n" +
+	"- ipfs://bafybeiemxf5abjwjbikoz4mc3a3dla6ual3jsgpdr4cjr3oz3evfyavhwq/\n" +
+	"- https://example.com/my-web2-claim.json"
+
+var ethereumWeb3Message = Web3Message{
+	Chain:          Web3ChainEthereum,
+	Domain:         "service.invalid",
+	Address:        "0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2",
+	Statement:      "I accept the ServiceOrg Terms of Service: https://service.invalid/tos",
+	URI:            "https://service.invalid/login",
+	Version:        "1",
+	ChainID:        "1",
+	Nonce:          "32891756",
+	IssuedAt:       time.Date(2021, 9, 30, 16, 25, 24, 0, time.UTC),
+	ExpirationTime: time.Date(2021, 9, 30, 16, 35, 24, 0, time.UTC),
+	NotBefore:      time.Date(2021, 9, 30, 16, 25, 24, 0, time.UTC),
+	RequestID:      "req-1",
+	Resources: []string{
+		"ipfs://bafybeiemxf5abjwjbikoz4mc3a3dla6ual3jsgpdr4cjr3oz3evfyavhwq/",
+		"https://example.com/my-web2-claim.json",
+	},
+}
+
+var solanaWeb3Message = Web3Message{
+	Chain:     Web3ChainSolana,
+	Domain:    "app.example.com",
+	Address:   solanaAddress,
+	Statement: "Sign in to Example",
+	URI:       "https://app.example.com",
+	Version:   "1",
+	ChainID:   "mainnet",
+	Nonce:     "32891756",
+	IssuedAt:  time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
+}
+
+var web3MessageStringTests = []struct {
+	name     string
+	message  Web3Message
+	expected string
+}{
+	{
+		name:     "ethereum with all fields",
+		message:  ethereumWeb3Message,
+		expected: ethereumMessage,
+	},
+	{
+		name:     "solana",
+		message:  solanaWeb3Message,
+		expected: solanaMessage,
+	},
+	{
+		name: "ethereum without statement defaults version",
+		message: Web3Message{
+			Chain:    Web3ChainEthereum,
+			Domain:   "app.example.com",
+			Address:  "0xabc",
+			URI:      "https://app.example.com",
+			ChainID:  "1",
+			Nonce:    "n1",
+			IssuedAt: time.Date(2024, 5, 1, 11, 0, 0, 0, time.FixedZone("BST", 3600)),
+		},
+		expected: "app.example.com wants you to sign in with your Ethereum account:\n0xabc\n\n\n" +
+			"URI: https://app.example.com\nVersion: 1\nChain ID: 1\nNonce: n1\nIssued At: 2024-05-01T10:00:00.000Z",
+	},
+	{
+		name: "solana without statement",
+		message: Web3Message{
+			Chain:    Web3ChainSolana,
+			Domain:   "app.example.com",
+			Address:  solanaAddress,
+			URI:      "https://app.example.com",
+			IssuedAt: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
+		},
+		expected: "app.example.com wants you to sign in with your Solana account:\n" + solanaAddress + "\n\n" +
+			"URI: https://app.example.com\nVersion: 1\nIssued At: 2024-05-01T10:00:00.000Z",
+	},
+}
+
+func TestWeb3Message_String(t *testing.T) {
+	for _, tt := range web3MessageStringTests {
+		assert.Equal(t, tt.message.String(), tt.expected)
+	}
+}
+
+var parseWeb3MessageTests = []struct {
+	name          string
+	message       string
+	expected      *Web3Message
+	expectedError error
+}{
+	{
+		name:          "ethereum",
+		message:       ethereumMessage,
+		expected:      &ethereumWeb3Message,
+		expectedError: nil,
+	},
+	{
+		name:          "solana",
+		message:       solanaMessage,
+		expected:      &solanaWeb3Message,
+		expectedError: nil,
+	},
+	{
+		name: "ethereum without statement",
+		message: "app.example.com wants you to sign in with your Ethereum account:\n0xabc\n\n\n" +
+			"URI: https://app.example.com\nVersion: 1\nChain ID: 1\nNonce: n1\nIssued At: 2024-05-01T10:00:00Z",
+		expected: &Web3Message{
+			Chain:    Web3ChainEthereum,
+			Domain:   "app.example.com",
+			Address:  "0xabc",
+			URI:      "https://app.example.com",
+			Version:  "1",
+			ChainID:  "1",
+			Nonce:    "n1",
+			IssuedAt: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
+		},
+		expectedError: nil,
+	},
+	{
+		name:          "single line",
+		message:       "hello",
+		expected:      nil,
+		expectedError: errors.New("invalid web3 sign in message"),
+	},
+	{
+		name:          "unknown header",
+		message:       "app.example.com wants you to sign in with your Bitcoin account:\nabc",
+		expected:      nil,
+		expectedError: errors.New("invalid web3 sign in message: unexpected header \"app.example.com wants you to sign in with your Bitcoin account:\""),
+	},
+	{
+		name:          "missing required fields",
+		message:       "app.example.com wants you to sign in with your Ethereum account:\n0xabc",
+		expected:      nil,
+		expectedError: errors.New("invalid web3 sign in message: URI and Issued At are required"),
+	},
+	{
+		name:          "line without field",
+		message:       "app.example.com wants you to sign in with your Ethereum account:\n0xabc\n\n\nURI: https://app.example.com\nbad",
+		expected:      nil,
+		expectedError: errors.New("invalid web3 sign in message: unexpected line \"bad\""),
+	},
+	{
+		name:          "unknown field",
+		message:       "app.example.com wants you to sign in with your Ethereum account:\n0xabc\n\n\nURI: https://app.example.com\nColour: red",
+		expected:      nil,
+		expectedError: errors.New("invalid web3 sign in message: unexpected field \"Colour\""),
+	},
+	{
+		name:          "invalid time",
+		message:       "app.example.com wants you to sign in with your Ethereum account:\n0xabc\n\n\nURI: https://app.example.com\nIssued At: yesterday",
+		expected:      nil,
+		expectedError: errors.New("invalid web3 sign in message: Issued At: parsing time \"yesterday\" as \"2006-01-02T15:04:05.999999999Z07:00\": cannot parse \"yesterday\" as \"2006\""),
+	},
+}
+
+func TestParseWeb3Message(t *testing.T) {
+	for _, tt := range parseWeb3MessageTests {
+		result, err := ParseWeb3Message(tt.message)
+
+		if tt.expectedError != nil {
+			assert.Equal(t, err.Error(), tt.expectedError.Error())
+			assert.Equal(t, errors.Is(err, ErrInvalidWeb3Message), true)
+		} else {
+			assert.Equal(t, err, nil)
+		}
+
+		assert.Equal(t, result, tt.expected)
+	}
+}
+
+var web3MessageValidateTests = []struct {
+	name          string
+	now           time.Time
+	expectedError error
+}{
+	{
+		name:          "within window",
+		now:           time.Date(2021, 9, 30, 16, 30, 0, 0, time.UTC),
+		expectedError: nil,
+	},
+	{
+		name:          "expired",
+		now:           time.Date(2021, 9, 30, 16, 35, 24, 0, time.UTC),
+		expectedError: ErrWeb3MessageExpired,
+	},
+	{
+		name:          "not yet valid",
+		now:           time.Date(2021, 9, 30, 16, 0, 0, 0, time.UTC),
+		expectedError: ErrWeb3MessageNotYetValid,
+	},
+}
+
+func TestWeb3Message_Validate(t *testing.T) {
+	for _, tt := range web3MessageValidateTests {
+		assert.Equal(t, ethereumWeb3Message.Validate(tt.now), tt.expectedError)
+	}
+
+	assert.Equal(t, solanaWeb3Message.Validate(time.Now()), nil)
+}
+
+var verifySolanaSignatureTests = []struct {
+	name          string
+	message       string
+	address       string
+	expectedError error
+}{
+	{
+		name:          "valid signature",
+		message:       solanaMessage,
+		address:       solanaAddress,
+		expectedError: nil,
+	},
+	{
+		name:          "tampered message",
+		message:       solanaMessage + "\nRequest ID: 1",
+		address:       solanaAddress,
+		expectedError: ErrInvalidWeb3Signature,
+	},
+	{
+		name:          "invalid base58",
+		message:       solanaMessage,
+		address:       "0OIl",
+		expectedError: ErrInvalidSolanaAddress,
+	},
+	{
+		name:          "wrong key length",
+		message:       solanaMessage,
+		address:       "abc",
+		expectedError: ErrInvalidSolanaAddress,
+	},
+}
+
+func TestVerifySolanaSignature(t *testing.T) {
+	signature, _ := hex.DecodeString(solanaSignature)
+
+	for _, tt := range verifySolanaSignatureTests {
+		assert.Equal(t, VerifySolanaSignature(tt.message, tt.address, signature), tt.expectedError)
+	}
+}
+
+func TestBase58(t *testing.T) {
+	decoded, err := base58Decode("11" + "2g")
+
+	assert.Equal(t, err, nil)
+	assert.Equal(t, decoded, []byte{0, 0, 0x61})
+
+	decoded, err = base58Decode(solanaAddress)
+
+	assert.Equal(t, err, nil)
+	assert.Equal(t, len(decoded), 32)
+}
+
+var signInWithWeb3Tests = []struct {
+	name           string
+	credentials    Web3Credentials
+	expectedBody   web3Request
+	authResponse   *AuthResponse
+	sendRequestErr error
+	resultErr      error
+}{
+	{
+		name:        "successful solana sign in with captcha",
+		credentials: Web3Credentials{Chain: Web3ChainSolana, Message: solanaMessage, Signature: "c2ln", Options: AuthOptions{CaptchaToken: "captcha123"}},
+		expectedBody: web3Request{
+			Web3Credentials: Web3Credentials{Chain: Web3ChainSolana, Message: solanaMessage, Signature: "c2ln", Options: AuthOptions{CaptchaToken: "captcha123"}},
+			Security:        &metaSecurity{CaptchaToken: "captcha123"},
+		},
+		authResponse: &AuthResponse{
+			Status: http.StatusOK,
+			Data:   &Authenticated{AccessToken: "cba321", User: User{ID: "abc123"}},
+		},
+		sendRequestErr: nil,
+		resultErr:      nil,
+	},
+	{
+		name:        "failed ethereum sign in with send request error",
+		credentials: Web3Credentials{Chain: Web3ChainEthereum, Message: ethereumMessage, Signature: "0x1234"},
+		expectedBody: web3Request{
+			Web3Credentials: Web3Credentials{Chain: Web3ChainEthereum, Message: ethereumMessage, Signature: "0x1234"},
+		},
+		authResponse:   nil,
+		sendRequestErr: errors.New("send request error"),
+		resultErr:      errors.New("send request error"),
+	},
+}
+
+func TestAuth_SignInWithWeb3(t *testing.T) {
+	for _, tt := range signInWithWeb3Tests {
+		client := new(clientMock)
+		sut := &Auth{
+			client: client,
+		}
+
+		client.On("createAndSendRequest", http.MethodPost, "token?grant_type=web3", tt.expectedBody, &Authenticated{}).
+			Return(tt.authResponse, tt.sendRequestErr)
+
+		result, err := sut.SignInWithWeb3(tt.credentials)
+
+		if err != nil {
+			assert.Equal(t, err.Error(), tt.resultErr.Error())
+			assert.Equal(t, result, tt.authResponse)
+		} else {
+			assert.Equal(t, err, nil)
+			assert.Equal(t, result, tt.authResponse)
+		}
+	}
+}